@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"floppy-go/internal/config"
 	"floppy-go/internal/context"
@@ -37,7 +38,11 @@ func main() {
 	root.AddCommand(cmdUpdateLib())
 	root.AddCommand(cmdAddLib())
 	root.AddCommand(cmdSetup())
+	root.AddCommand(cmdRun())
+	root.AddCommand(cmdEnv())
+	root.AddCommand(cmdShell())
 	root.AddCommand(cmdLogs())
+	root.AddCommand(cmdLogWriter())
 	root.AddCommand(cmdDoctor())
 	root.AddCommand(cmdSetContext())
 	root.AddCommand(cmdVersion())
@@ -69,16 +74,15 @@ func cmdUp() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			_ = build
 			if !noPTY && os.Getenv("FLOPPY_NO_PTY") == "1" {
 				noPTY = true
 			}
-			return mgr.Up(args, detached, force, noPTY)
+			return mgr.Up(args, detached, force, noPTY, build)
 		},
 	}
 	cmd.Flags().BoolVarP(&detached, "detached", "d", false, "Run in background")
 	cmd.Flags().BoolVar(&force, "force", false, "Kill existing processes using required ports")
-	cmd.Flags().BoolVar(&build, "build", false, "Build services before starting (reserved)")
+	cmd.Flags().BoolVar(&build, "build", false, "Rebuild services whose dependencies changed before starting")
 	cmd.Flags().BoolVar(&noPTY, "no-pty", false, "Disable PTY (useful if PTY is blocked)")
 	return cmd
 }
@@ -94,17 +98,24 @@ func cmdStop() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			_ = remove
-			return mgr.Stop(args, forcePortKill)
+			if err := mgr.Stop(args, forcePortKill); err != nil {
+				return err
+			}
+			if !remove {
+				return nil
+			}
+			return mgr.Remove(args, manager.RemoveOptions{})
 		},
 	}
-	cmd.Flags().BoolVar(&remove, "remove", false, "Remove stopped services (reserved)")
+	cmd.Flags().BoolVar(&remove, "remove", false, "Also remove state, logs and cgroups of stopped services")
 	cmd.Flags().BoolVar(&forcePortKill, "force-port-kill", false, "Fallback to killing any process bound to service ports")
 	return cmd
 }
 
 func cmdDown() *cobra.Command {
 	var remove bool
+	var volumes bool
+	var yes bool
 	var forcePortKill bool
 	cmd := &cobra.Command{
 		Use:   "down [service ...]",
@@ -114,17 +125,29 @@ func cmdDown() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			_ = remove
-			return mgr.Stop(args, forcePortKill)
+			if volumes && !remove {
+				return fmt.Errorf("--volumes requires --remove")
+			}
+			if err := mgr.Stop(args, forcePortKill); err != nil {
+				return err
+			}
+			if !remove {
+				return nil
+			}
+			return mgr.Remove(args, manager.RemoveOptions{Volumes: volumes, Yes: yes})
 		},
 	}
-	cmd.Flags().BoolVar(&remove, "remove", false, "Remove stopped services (reserved)")
+	cmd.Flags().BoolVar(&remove, "remove", false, "Also remove state, logs and cgroups of stopped services")
+	cmd.Flags().BoolVar(&volumes, "volumes", false, "With --remove, also drop the service databases (asks for confirmation)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
 	cmd.Flags().BoolVar(&forcePortKill, "force-port-kill", false, "Fallback to killing any process bound to service ports")
 	return cmd
 }
 
 func cmdPs() *cobra.Command {
 	var quiet bool
+	var format string
+	var watch bool
 	cmd := &cobra.Command{
 		Use:   "ps",
 		Short: "List running services",
@@ -133,11 +156,12 @@ func cmdPs() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			mgr.Ps(quiet)
-			return nil
+			return mgr.Ps(manager.PsOptions{Quiet: quiet, Format: format, Watch: watch})
 		},
 	}
 	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only display service names")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format (table, json)")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Refresh every 2 seconds")
 	return cmd
 }
 
@@ -292,27 +316,101 @@ func cmdSetup() *cobra.Command {
 	return cmd
 }
 
+func cmdRun() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run TASK",
+		Short: "Run a one-shot task (and the tasks it depends on)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := loadManager()
+			if err != nil {
+				return err
+			}
+			return mgr.RunTask(args[0])
+		},
+	}
+}
+
+func cmdEnv() *cobra.Command {
+	var format string
+	var noInherit bool
+	var reveal bool
+	cmd := &cobra.Command{
+		Use:   "env SERVICE",
+		Short: "Print the environment a service is started with",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := loadManager()
+			if err != nil {
+				return err
+			}
+			return mgr.Env(args[0], manager.EnvOptions{Format: format, NoInherit: noInherit, Reveal: reveal})
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "dotenv", "Output format (dotenv, json, export)")
+	cmd.Flags().BoolVar(&noInherit, "no-inherit", false, "Only print variables set by floppy, not the inherited OS environment")
+	cmd.Flags().BoolVar(&reveal, "reveal", false, "Print secret values instead of masking them (for eval or env files)")
+	return cmd
+}
+
+func cmdShell() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell SERVICE",
+		Short: "Open a shell in the service directory with the service's environment",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := loadManager()
+			if err != nil {
+				return err
+			}
+			return mgr.Shell(args[0])
+		},
+	}
+}
+
 func cmdLogs() *cobra.Command {
-	var follow bool
-	var tail int
+	var opts manager.LogsOptions
+	var since string
 	cmd := &cobra.Command{
 		Use:   "logs SERVICE",
 		Short: "Show logs for a service",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if opts.Since, err = manager.ParseSince(since, time.Now()); err != nil {
+				return err
+			}
 			mgr, err := loadManager()
 			if err != nil {
 				return err
 			}
-			mgr.Logs(args[0], follow, tail)
-			return nil
+			return mgr.Logs(args[0], opts)
 		},
 	}
-	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow log output")
-	cmd.Flags().IntVar(&tail, "tail", 100, "Number of lines to show from the end")
+	cmd.Flags().BoolVar(&opts.Follow, "follow", false, "Follow log output")
+	cmd.Flags().IntVar(&opts.Tail, "tail", 100, "Number of lines to show from the end (0 for all)")
+	cmd.Flags().StringVar(&since, "since", "", "Only show lines received since a duration ago (10m) or an RFC 3339 time")
+	cmd.Flags().BoolVarP(&opts.Timestamps, "timestamps", "t", false, "Show receive times")
 	return cmd
 }
 
+// cmdLogWriter persists a detached service's output; `up -d` starts it.
+func cmdLogWriter() *cobra.Command {
+	return &cobra.Command{
+		Use:    "log-writer SERVICE",
+		Short:  "Persist service output read from stdin",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := loadManager()
+			if err != nil {
+				return err
+			}
+			return mgr.PersistOutput(args[0], os.Stdin)
+		},
+	}
+}
+
 func cmdSetContext() *cobra.Command {
 	var file string
 	var show bool
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"floppy-go/internal/context"
 	"gopkg.in/yaml.v3"
@@ -12,9 +15,16 @@ import (
 
 type Config struct {
 	Stats    *StatsConfig          `yaml:"stats"`
+	TUI      *TUIConfig            `yaml:"tui"`
+	Notify   *NotifyConfig         `yaml:"notify"`
 	Env      map[string]any        `yaml:"env"`
 	Services map[string]ServiceDef `yaml:"services"`
+	Tasks    map[string]TaskDef    `yaml:"tasks"`
 	Bundles  map[string][]string   `yaml:"bundles"`
+	// Secrets names variables (or globs such as *_SALT) whose values are
+	// redacted wherever floppy prints them, on top of the names that look
+	// secret (*SECRET*, *PASSWORD*, *TOKEN*, *_KEY, ...).
+	Secrets []string `yaml:"secrets"`
 }
 
 // StatsConfig holds optional monitoring endpoints (e.g. Postgres, Docker).
@@ -29,6 +39,36 @@ type DBStatsConfig struct {
 	Enabled bool   `yaml:"enabled"`
 }
 
+// TUIConfig tunes the `floppy up` interface.
+type TUIConfig struct {
+	// Scrollback is the number of log lines kept in memory per service;
+	// older lines are spilled to disk and loaded back when scrolling up.
+	Scrollback int `yaml:"scrollback"`
+	// Theme is "dark" (default) or "light".
+	Theme string `yaml:"theme"`
+	// Keys remaps actions to comma separated keys, e.g. quit: ctrl+q.
+	// Press ? in the TUI for the action names.
+	Keys map[string]string `yaml:"keys"`
+	// Colors pins service name colors (ANSI numbers or #rrggbb).
+	Colors map[string]string `yaml:"colors"`
+}
+
+// NotifyConfig alerts when a service under `floppy up` exits unexpectedly,
+// fails to start or restart, or logs a line matching one of Patterns.
+type NotifyConfig struct {
+	// Bell rings the terminal bell.
+	Bell bool `yaml:"bell"`
+	// Desktop sends a desktop notification escape: "osc9" (iTerm2,
+	// WezTerm, Windows Terminal) or "osc777" (foot, Ghostty, urxvt); empty
+	// disables it.
+	Desktop string `yaml:"desktop"`
+	// Command runs through sh -c with FLOPPY_SERVICE, FLOPPY_EVENT and
+	// FLOPPY_MESSAGE set, e.g. notify-send floppy "$FLOPPY_MESSAGE".
+	Command string `yaml:"command"`
+	// Patterns are regular expressions matched against each log line.
+	Patterns []string `yaml:"patterns"`
+}
+
 // DockerStatsConfig enables the Docker resource stats panel in the TUI.
 type DockerStatsConfig struct {
 	Enabled bool `yaml:"enabled"`
@@ -45,6 +85,54 @@ type ServiceDef struct {
 	HMRPort       int            `yaml:"hmr_port"`
 	WSPort        int            `yaml:"ws_port"`
 	DockerCommand string         `yaml:"docker_command"`
+	Limits        *LimitsConfig  `yaml:"limits"`
+	Watch         *WatchConfig   `yaml:"watch"`
+	Hooks         *HooksConfig   `yaml:"hooks"`
+	DependsOn     []string       `yaml:"depends_on"`
+	Build         string         `yaml:"build"`
+	BuildInputs   []string       `yaml:"build_inputs"`
+	Migrate       *bool          `yaml:"migrate"` // false: `floppy setup` leaves migrations to a task
+}
+
+// TaskDef is a one-shot command (migration, seeder, codegen) that runs to
+// completion. Services list tasks in depends_on to have them run first; tasks
+// with Setup set also run as part of `floppy setup`.
+type TaskDef struct {
+	Path      string         `yaml:"path"`
+	Command   string         `yaml:"command"`
+	Env       map[string]any `yaml:"env"`
+	DependsOn []string       `yaml:"depends_on"`
+	Setup     bool           `yaml:"setup"`
+}
+
+// HooksConfig holds shell commands run in the service directory around the
+// service's lifecycle. OnFailure is "abort" (default) or "warn". Timeout
+// bounds each hook (default 10m for start hooks, 1m for stop hooks).
+type HooksConfig struct {
+	PreStart  string        `yaml:"pre_start"`
+	PostStart string        `yaml:"post_start"`
+	PreStop   string        `yaml:"pre_stop"`
+	PostStop  string        `yaml:"post_stop"`
+	OnFailure string        `yaml:"on_failure"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+// WatchConfig restarts a service when files under Paths change. Paths are
+// relative to the service directory (default: the whole directory); Ignore
+// holds glob patterns matched against names and relative paths.
+type WatchConfig struct {
+	Paths    []string      `yaml:"paths"`
+	Ignore   []string      `yaml:"ignore"`
+	Debounce time.Duration `yaml:"debounce"`
+}
+
+// LimitsConfig caps a service's resources. Memory takes a size such as "512m"
+// or "1g", CPU is a number of cores (1.5 = one and a half) and NoFile is the
+// open-files rlimit.
+type LimitsConfig struct {
+	Memory string  `yaml:"memory"`
+	CPU    float64 `yaml:"cpu"`
+	NoFile uint64  `yaml:"nofile"`
 }
 
 func LoadConfig(configPath string) (*Config, string, error) {
@@ -69,6 +157,9 @@ func LoadConfig(configPath string) (*Config, string, error) {
 	if cfg.Services == nil {
 		cfg.Services = map[string]ServiceDef{}
 	}
+	if cfg.Tasks == nil {
+		cfg.Tasks = map[string]TaskDef{}
+	}
 	if cfg.Bundles == nil {
 		cfg.Bundles = map[string][]string{}
 	}
@@ -163,3 +254,34 @@ func MergeEnv(base map[string]any, svc map[string]any) []string {
 	}
 	return out
 }
+
+// ParseSize parses a byte size such as "512m", "1g", "1.5GiB" or "4096".
+// Units are powers of 1024; a bare number is bytes.
+func ParseSize(s string) (int64, error) {
+	raw := strings.TrimSpace(s)
+	if raw == "" {
+		return 0, errors.New("empty size")
+	}
+	lower := strings.ToLower(raw)
+	lower = strings.TrimSuffix(lower, "ib")
+	lower = strings.TrimSuffix(lower, "b")
+	mult := int64(1)
+	switch {
+	case strings.HasSuffix(lower, "k"):
+		mult = 1 << 10
+	case strings.HasSuffix(lower, "m"):
+		mult = 1 << 20
+	case strings.HasSuffix(lower, "g"):
+		mult = 1 << 30
+	case strings.HasSuffix(lower, "t"):
+		mult = 1 << 40
+	}
+	if mult > 1 {
+		lower = lower[:len(lower)-1]
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(lower), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", raw)
+	}
+	return int64(n * float64(mult)), nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestServiceNames(t *testing.T) {
@@ -134,6 +135,89 @@ bundles:
 	}
 }
 
+func TestLoadConfig_Watch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "services.yaml")
+	const yaml = `
+services:
+  worker:
+    type: worker
+    watch:
+      paths: [app]
+      ignore: ["*.pyc"]
+      debounce: 750ms
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	w := cfg.Services["worker"].Watch
+	if w == nil || len(w.Paths) != 1 || w.Paths[0] != "app" || w.Debounce != 750*time.Millisecond {
+		t.Errorf("watch: got %+v", w)
+	}
+}
+
+func TestLoadConfig_Hooks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "services.yaml")
+	const yaml = `
+services:
+  api:
+    type: api
+    hooks:
+      pre_start: alembic upgrade heads
+      pre_stop: ./flush-queue.sh
+      on_failure: warn
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	h := cfg.Services["api"].Hooks
+	if h == nil || h.PreStart != "alembic upgrade heads" || h.PreStop != "./flush-queue.sh" || h.OnFailure != "warn" {
+		t.Errorf("hooks: got %+v", h)
+	}
+}
+
+func TestLoadConfig_Tasks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "services.yaml")
+	const yaml = `
+tasks:
+  migrate:
+    path: api
+    command: poetry run alembic upgrade heads
+    setup: true
+  seed:
+    path: api
+    command: poetry run python -m app.seed
+    depends_on: [migrate]
+services:
+  api:
+    type: api
+    depends_on: [seed]
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(cfg.Tasks) != 2 || !cfg.Tasks["migrate"].Setup || cfg.Tasks["seed"].DependsOn[0] != "migrate" {
+		t.Errorf("tasks: got %+v", cfg.Tasks)
+	}
+	if deps := cfg.Services["api"].DependsOn; len(deps) != 1 || deps[0] != "seed" {
+		t.Errorf("api depends_on: got %v", deps)
+	}
+}
+
 func TestLoadConfig_DefaultsNilMaps(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "services.yaml")
@@ -144,7 +228,32 @@ func TestLoadConfig_DefaultsNilMaps(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if cfg.Env == nil || cfg.Services == nil || cfg.Bundles == nil {
-		t.Errorf("LoadConfig should default nil maps: Env=%v Services=%v Bundles=%v", cfg.Env, cfg.Services, cfg.Bundles)
+	if cfg.Env == nil || cfg.Services == nil || cfg.Tasks == nil || cfg.Bundles == nil {
+		t.Errorf("LoadConfig should default nil maps: Env=%v Services=%v Tasks=%v Bundles=%v", cfg.Env, cfg.Services, cfg.Tasks, cfg.Bundles)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"4096", 4096},
+		{"512m", 512 << 20},
+		{"1g", 1 << 30},
+		{"1.5GiB", 3 << 29},
+		{"2K", 2048},
+		{"100MB", 100 << 20},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "lots", "-1g"} {
+		if _, err := ParseSize(bad); err == nil {
+			t.Errorf("ParseSize(%q): want error", bad)
+		}
 	}
 }
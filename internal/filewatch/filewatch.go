@@ -0,0 +1,76 @@
+// Package filewatch reports file changes under a set of paths. On Linux it
+// uses inotify; elsewhere it falls back to polling modification times.
+package filewatch
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// IgnoreFunc reports whether path (and, for directories, everything below
+// it) should be skipped.
+type IgnoreFunc func(path string, isDir bool) bool
+
+// Watcher delivers the paths of changed files on Events until Close.
+type Watcher struct {
+	events chan string
+	ignore IgnoreFunc
+	done   chan struct{}
+	impl
+}
+
+// New starts watching roots. Directories are watched recursively; roots that
+// do not exist are skipped.
+func New(roots []string, ignore IgnoreFunc) (*Watcher, error) {
+	if ignore == nil {
+		ignore = func(string, bool) bool { return false }
+	}
+	w := &Watcher{
+		events: make(chan string, 64),
+		ignore: ignore,
+		done:   make(chan struct{}),
+	}
+	if err := w.start(roots); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Events returns the channel of changed paths.
+func (w *Watcher) Events() <-chan string {
+	return w.events
+}
+
+// Close stops the watcher.
+func (w *Watcher) Close() error {
+	select {
+	case <-w.done:
+		return nil
+	default:
+	}
+	close(w.done)
+	return w.stop()
+}
+
+func (w *Watcher) emit(path string) {
+	select {
+	case w.events <- path:
+	case <-w.done:
+	default:
+		// Drop when the consumer is behind; it only needs to know something changed.
+	}
+}
+
+// walkDirs calls fn for root and every directory below it that is not ignored.
+func (w *Watcher) walkDirs(root string, fn func(dir string)) {
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != root && w.ignore(path, true) {
+			return filepath.SkipDir
+		}
+		fn(path)
+		return nil
+	})
+}
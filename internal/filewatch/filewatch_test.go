@@ -0,0 +1,48 @@
+package filewatch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatcherReportsChanges(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "skip"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	w, err := New([]string{dir}, func(path string, isDir bool) bool {
+		return strings.HasSuffix(path, "skip")
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer w.Close()
+
+	// Give the poller a baseline scan before changing anything.
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(dir, "skip", "ignored.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(dir, "app.py")
+	if err := os.WriteFile(target, []byte("print(1)"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.After(3 * time.Second)
+	for {
+		select {
+		case path := <-w.Events():
+			if strings.Contains(path, "ignored") {
+				t.Fatalf("got event for ignored path %s", path)
+			}
+			if path == target {
+				return
+			}
+		case <-deadline:
+			t.Fatal("no event for changed file")
+		}
+	}
+}
@@ -0,0 +1,128 @@
+//go:build linux
+
+package filewatch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const watchMask = syscall.IN_CREATE | syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY |
+	syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+type impl struct {
+	file *os.File
+	fd   int
+
+	mu    sync.Mutex
+	dirs  map[int]string          // watch descriptor -> directory
+	files map[int]map[string]bool // watch descriptor -> the only names to report (file roots)
+}
+
+func (w *Watcher) start(roots []string) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return err
+	}
+	w.fd = fd
+	// A non-blocking fd wrapped in os.File uses the runtime poller, so Close
+	// unblocks the pending Read.
+	w.file = os.NewFile(uintptr(fd), "inotify")
+	w.dirs = map[int]string{}
+	w.files = map[int]map[string]bool{}
+
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			w.walkDirs(root, w.addDir)
+			continue
+		}
+		// Watch the parent of a single file so editors that replace the
+		// file on save are still seen.
+		wd, err := syscall.InotifyAddWatch(fd, filepath.Dir(root), watchMask)
+		if err != nil {
+			continue
+		}
+		w.mu.Lock()
+		w.dirs[wd] = filepath.Dir(root)
+		if w.files[wd] == nil {
+			w.files[wd] = map[string]bool{}
+		}
+		w.files[wd][filepath.Base(root)] = true
+		w.mu.Unlock()
+	}
+
+	go w.readLoop()
+	return nil
+}
+
+func (w *Watcher) addDir(dir string) {
+	wd, err := syscall.InotifyAddWatch(w.fd, dir, watchMask)
+	if err != nil {
+		return
+	}
+	w.mu.Lock()
+	w.dirs[wd] = dir
+	delete(w.files, wd) // a directory watch reports everything
+	w.mu.Unlock()
+}
+
+func (w *Watcher) readLoop() {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			return
+		}
+		for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			nameBytes := buf[off+syscall.SizeofInotifyEvent : off+syscall.SizeofInotifyEvent+int(ev.Len)]
+			off += syscall.SizeofInotifyEvent + int(ev.Len)
+			w.handle(int(ev.Wd), ev.Mask, cString(nameBytes))
+		}
+	}
+}
+
+func (w *Watcher) handle(wd int, mask uint32, name string) {
+	w.mu.Lock()
+	dir, ok := w.dirs[wd]
+	only := w.files[wd]
+	w.mu.Unlock()
+	if !ok || name == "" {
+		return
+	}
+	if only != nil && !only[name] {
+		return
+	}
+	path := filepath.Join(dir, name)
+	isDir := mask&syscall.IN_ISDIR != 0
+	if w.ignore(path, isDir) {
+		return
+	}
+	if isDir {
+		if mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 && only == nil {
+			w.walkDirs(path, w.addDir)
+		}
+		return
+	}
+	w.emit(path)
+}
+
+func (w *Watcher) stop() error {
+	return w.file.Close()
+}
+
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
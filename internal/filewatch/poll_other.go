@@ -0,0 +1,85 @@
+//go:build !linux
+
+package filewatch
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// pollInterval is how often roots are rescanned where inotify is unavailable.
+const pollInterval = time.Second
+
+type impl struct {
+	roots []string
+	seen  map[string]time.Time
+}
+
+func (w *Watcher) start(roots []string) error {
+	w.roots = roots
+	w.seen = w.scan()
+	go w.pollLoop()
+	return nil
+}
+
+func (w *Watcher) pollLoop() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			current := w.scan()
+			for path, mod := range current {
+				if prev, ok := w.seen[path]; !ok || !prev.Equal(mod) {
+					w.emit(path)
+				}
+			}
+			for path := range w.seen {
+				if _, ok := current[path]; !ok {
+					w.emit(path)
+				}
+			}
+			w.seen = current
+		}
+	}
+}
+
+func (w *Watcher) scan() map[string]time.Time {
+	out := map[string]time.Time{}
+	for _, root := range w.roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			out[root] = info.ModTime()
+			continue
+		}
+		w.walkDirs(root, func(dir string) {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return
+			}
+			for _, e := range entries {
+				if e.IsDir() {
+					continue
+				}
+				path := filepath.Join(dir, e.Name())
+				if w.ignore(path, false) {
+					continue
+				}
+				if fi, err := e.Info(); err == nil {
+					out[path] = fi.ModTime()
+				}
+			}
+		})
+	}
+	return out
+}
+
+func (w *Watcher) stop() error {
+	return nil
+}
@@ -0,0 +1,199 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"floppy-go/internal/config"
+)
+
+// BuildRecord is the last successful build of a service.
+type BuildRecord struct {
+	InputsHash string `json:"inputs_hash"`
+	BuiltAt    string `json:"built_at"`
+}
+
+// serviceBuildCommand returns the shell command that builds the service's
+// dependencies, falling back to a default for the service type.
+func (m *Manager) serviceBuildCommand(svc config.ServiceDef) string {
+	if svc.Build != "" {
+		return svc.Build
+	}
+	switch {
+	case isPythonType(svc.Type):
+		return shellQuote(resolveTool("poetry", "FLOPPY_POETRY")) + " install"
+	case svc.Type == "portal":
+		return shellQuote(resolveTool("bun", "FLOPPY_BUN")) + " install"
+	}
+	return ""
+}
+
+// buildInputs returns the files whose contents decide whether a service needs
+// rebuilding, relative to the service directory.
+func buildInputs(svc config.ServiceDef) []string {
+	if len(svc.BuildInputs) > 0 {
+		return svc.BuildInputs
+	}
+	switch {
+	case isPythonType(svc.Type):
+		return []string{"pyproject.toml", "poetry.lock"}
+	case svc.Type == "portal":
+		return []string{"package.json", "bun.lockb", "bun.lock"}
+	}
+	return nil
+}
+
+// hashBuildInputs hashes the names and contents of the inputs under dir.
+// Patterns may be globs; missing files hash differently from empty ones.
+func hashBuildInputs(dir string, inputs []string) (string, error) {
+	files := []string{}
+	for _, pattern := range inputs {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return "", fmt.Errorf("build_inputs: %w", err)
+		}
+		if len(matches) == 0 {
+			files = append(files, filepath.Join(dir, pattern))
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, path := range files {
+		rel, _ := filepath.Rel(dir, path)
+		fmt.Fprintf(h, "%s\x00", rel)
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			fmt.Fprint(h, "missing\x00")
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprint(h, "\x00")
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// buildStatus reports whether the service has a build step and, if so,
+// whether its inputs changed since the last recorded build.
+func (m *Manager) buildStatus(name string, state ProcessState) (hash string, stale bool, err error) {
+	svc := m.Config.Services[name]
+	dir := servicePath(m.Root, name, svc.Path)
+	if m.serviceBuildCommand(svc) == "" {
+		return "", false, nil
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return "", false, nil
+	}
+	hash, err = hashBuildInputs(dir, buildInputs(svc))
+	if err != nil {
+		return "", false, err
+	}
+	return hash, state.Builds[name].InputsHash != hash, nil
+}
+
+// buildServices runs the build command of every service in names whose
+// inputs changed since its last build and records the result. Services
+// sharing a directory and build command (an API and its workers) are built
+// once.
+func (m *Manager) buildServices(names []string) error {
+	stateMu.Lock()
+	state := loadProcessState()
+	stateMu.Unlock()
+
+	built := map[string]string{} // dir + command -> service built
+	for _, name := range names {
+		hash, stale, err := m.buildStatus(name, state)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if hash == "" {
+			continue
+		}
+		if !stale {
+			fmt.Printf("✓ %s is up to date\n", name)
+			continue
+		}
+		svc := m.Config.Services[name]
+		key := servicePath(m.Root, name, svc.Path) + "\x00" + m.serviceBuildCommand(svc)
+		if other, ok := built[key]; ok {
+			fmt.Printf("✓ %s built with %s\n", name, other)
+		} else {
+			if err := m.runBuild(name); err != nil {
+				return err
+			}
+			built[key] = name
+		}
+		m.recordBuild(name, hash)
+	}
+	return nil
+}
+
+func (m *Manager) runBuild(name string) error {
+	svc := m.Config.Services[name]
+	command := m.serviceBuildCommand(svc)
+	dir := servicePath(m.Root, name, svc.Path)
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("%s: directory not found (%s)", name, dir)
+	}
+	fmt.Printf("🔨 Building %s: %s\n", name, command)
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = m.serviceEnv(name, svc)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: build failed: %w", name, err)
+	}
+	return nil
+}
+
+// recordBuild stores the input hash of a successful build.
+func (m *Manager) recordBuild(name, hash string) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	state := loadProcessState()
+	if state.Builds == nil {
+		state.Builds = map[string]BuildRecord{}
+	}
+	state.Builds[name] = BuildRecord{InputsHash: hash, BuiltAt: time.Now().Format(time.RFC3339)}
+	if err := saveProcessState(state); err != nil {
+		fmt.Printf("Warning: failed to persist build state: %v\n", err)
+	}
+}
+
+// staleBuilds returns a warning for each service whose build inputs changed
+// since it was last built. Services without a recorded build are not
+// reported: that is every service on first use.
+func (m *Manager) staleBuilds(names []string) []string {
+	stateMu.Lock()
+	state := loadProcessState()
+	stateMu.Unlock()
+
+	warnings := []string{}
+	for _, name := range names {
+		hash, stale, err := m.buildStatus(name, state)
+		if err != nil || hash == "" || !stale {
+			continue
+		}
+		if _, built := state.Builds[name]; !built {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("%s dependencies changed since the last build; run `floppy up --build`", name))
+	}
+	return warnings
+}
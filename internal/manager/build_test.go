@@ -0,0 +1,109 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"floppy-go/internal/config"
+)
+
+func Test_hashBuildInputs(t *testing.T) {
+	dir := t.TempDir()
+	inputs := []string{"package.json", "bun.lock"}
+
+	missing, err := hashBuildInputs(dir, inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	first, _ := hashBuildInputs(dir, inputs)
+	again, _ := hashBuildInputs(dir, inputs)
+	if first == missing || first != again {
+		t.Errorf("hash should change when an input appears and be stable otherwise: %s %s %s", missing, first, again)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"a":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := hashBuildInputs(dir, inputs); changed == first {
+		t.Error("hash should change with input contents")
+	}
+}
+
+func Test_buildServices(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("FLOPPY_STATE_FILE", filepath.Join(root, "state.json"))
+	svcDir := filepath.Join(root, "api")
+	if err := os.MkdirAll(svcDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	lock := filepath.Join(svcDir, "deps.lock")
+	if err := os.WriteFile(lock, []byte("v1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := New(&config.Config{Services: map[string]config.ServiceDef{
+		"api": {Type: "docker", Build: "echo built >> build.log", BuildInputs: []string{"deps.lock"}},
+	}}, "")
+	m.Root = root
+	builds := func() int {
+		data, _ := os.ReadFile(filepath.Join(svcDir, "build.log"))
+		return len(data) / len("built\n")
+	}
+
+	if w := m.staleBuilds([]string{"api"}); len(w) != 0 {
+		t.Errorf("never built: warnings = %v, want none", w)
+	}
+	if err := m.buildServices([]string{"api"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.buildServices([]string{"api"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := builds(); n != 1 {
+		t.Errorf("builds = %d, want 1 (second run is up to date)", n)
+	}
+	if w := m.staleBuilds([]string{"api"}); len(w) != 0 {
+		t.Errorf("after build: warnings = %v", w)
+	}
+
+	if err := os.WriteFile(lock, []byte("v2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if w := m.staleBuilds([]string{"api"}); len(w) != 1 {
+		t.Errorf("lockfile changed: warnings = %v", w)
+	}
+	if err := m.buildServices([]string{"api"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := builds(); n != 2 {
+		t.Errorf("builds = %d, want 2", n)
+	}
+}
+
+func Test_buildServices_sharedDirectory(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("FLOPPY_STATE_FILE", filepath.Join(root, "state.json"))
+	svcDir := filepath.Join(root, "orcha")
+	if err := os.MkdirAll(svcDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	build := "echo built >> build.log"
+	m := New(&config.Config{Services: map[string]config.ServiceDef{
+		"orcha":        {Type: "docker", Build: build},
+		"orcha-worker": {Type: "docker", Path: "orcha", Build: build},
+	}}, "")
+	m.Root = root
+
+	if err := m.buildServices([]string{"orcha", "orcha-worker"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(svcDir, "build.log"))
+	if string(data) != "built\n" {
+		t.Errorf("build.log = %q, want one build", data)
+	}
+	if w := m.staleBuilds([]string{"orcha", "orcha-worker"}); len(w) != 0 {
+		t.Errorf("after build: warnings = %v", w)
+	}
+}
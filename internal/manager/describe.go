@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"floppy-go/internal/config"
+	"floppy-go/internal/tui"
+)
+
+// describeService gathers what the TUI service detail panel shows: config,
+// the resolved command and the tracked process.
+func (m *Manager) describeService(name string) (tui.ServiceInfo, bool) {
+	info := tui.ServiceInfo{Name: name}
+	var vars []string
+	if svc, ok := m.Config.Services[name]; ok {
+		info.Type = svc.Type
+		info.Dir = servicePath(m.Root, name, svc.Path)
+		if cmd, err := m.buildCommand(name, svc); err != nil {
+			info.Command = "(" + err.Error() + ")"
+		} else {
+			info.Command = m.secrets().redact(shellJoin(cmd.Args))
+		}
+		info.Ports = servicePorts(svc)
+		vars = m.serviceVars(name, svc)
+	} else if task, ok := m.Config.Tasks[name]; ok {
+		info.Type = "task"
+		info.Dir = filepath.Join(m.Root, task.Path)
+		info.Command = m.secrets().redact(task.Command)
+		vars = config.MergeEnv(m.Config.Env, task.Env)
+	} else {
+		return info, false
+	}
+	for _, kv := range vars {
+		info.Env = append(info.Env, m.secrets().maskVar(kv))
+	}
+
+	stateMu.Lock()
+	entry, ok := loadProcessState().Entries[name]
+	stateMu.Unlock()
+	if ok {
+		info.PID = entry.PID
+		info.PGID = entry.PGID
+		info.Running = processAlive(entry.PID)
+		info.Restarts = entry.Restarts
+		info.ExitCode = entry.ExitCode
+		info.OOMKilled = entry.OOMKilled
+		info.StartedAt, _ = parseStartTime(entry.StartTime)
+		if entry.Cwd != "" {
+			info.Dir = entry.Cwd
+		}
+	}
+	return info, true
+}
+
+// servicePorts lists the ports a service listens on, labelled.
+func servicePorts(svc config.ServiceDef) []string {
+	ports := []string{}
+	if svc.Port > 0 {
+		ports = append(ports, fmt.Sprintf("%d (main)", svc.Port))
+	}
+	if svc.HMRPort > 0 {
+		ports = append(ports, fmt.Sprintf("%d (hmr)", svc.HMRPort))
+	}
+	if svc.WSPort > 0 {
+		ports = append(ports, fmt.Sprintf("%d (ws)", svc.WSPort))
+	}
+	return ports
+}
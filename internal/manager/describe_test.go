@@ -0,0 +1,73 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"floppy-go/internal/config"
+)
+
+func Test_maskEnvVar(t *testing.T) {
+	cases := map[string]string{
+		"DB_PASSWORD=hunter2":                      "DB_PASSWORD=" + secretMask,
+		"STRIPE_API_KEY=sk_live_1":                 "STRIPE_API_KEY=" + secretMask,
+		"GITHUB_TOKEN=ghp_x":                       "GITHUB_TOKEN=" + secretMask,
+		"DB_HOST=localhost":                        "DB_HOST=localhost",
+		"EMPTY_SECRET=":                            "EMPTY_SECRET=",
+		"DATABASE_URL=postgres://app:pw@db:5432/x": "DATABASE_URL=postgres://app:" + secretMask + "@db:5432/x",
+		"REDIS_URL=redis://db:6379/0":              "REDIS_URL=redis://db:6379/0",
+	}
+	for in, want := range cases {
+		if got := maskEnvVar(in); got != want {
+			t.Errorf("maskEnvVar(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func Test_describeService(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("FLOPPY_STATE_FILE", filepath.Join(tmp, "state.json"))
+	code := 3
+	if err := saveProcessState(ProcessState{Entries: map[string]ProcessEntry{
+		"api": {Service: "api", PID: os.Getpid(), PGID: 42, Restarts: 2, ExitCode: &code, StartTime: "Mon Oct  6 09:15:02 2025"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	m := New(&config.Config{
+		Env: map[string]any{"DB_PASSWORD": "pw"},
+		Services: map[string]config.ServiceDef{
+			"api": {Type: "api", Port: 8000, Command: "serve", Env: map[string]any{"MODE": "dev"}},
+		},
+		Tasks: map[string]config.TaskDef{"migrate": {Path: "api", Command: "alembic upgrade heads"}},
+	}, filepath.Join(tmp, "services.yaml"))
+
+	info, ok := m.describeService("api")
+	if !ok {
+		t.Fatal("describeService(api): not found")
+	}
+	if info.Type != "api" || info.Dir != filepath.Join(m.Root, "api") || !strings.HasSuffix(info.Command, "run serve") {
+		t.Errorf("config fields: %+v", info)
+	}
+	if info.PID != os.Getpid() || info.PGID != 42 || !info.Running || info.Restarts != 2 || info.ExitCode == nil || *info.ExitCode != 3 {
+		t.Errorf("process fields: %+v", info)
+	}
+	if info.StartedAt.IsZero() || info.StartedAt.Day() != 6 {
+		t.Errorf("StartedAt = %v", info.StartedAt)
+	}
+	env := strings.Join(info.Env, "\n")
+	if !strings.Contains(env, "DB_PASSWORD="+secretMask) || strings.Contains(env, "=pw") || !strings.Contains(env, "MODE=dev") || !strings.Contains(env, "PORT=8000") || !strings.Contains(env, "PGAPPNAME=api") {
+		t.Errorf("env = %v", info.Env)
+	}
+	if len(info.Ports) != 1 || info.Ports[0] != "8000 (main)" {
+		t.Errorf("ports = %v", info.Ports)
+	}
+
+	if info, ok := m.describeService("migrate"); !ok || info.Type != "task" || info.Command != "alembic upgrade heads" {
+		t.Errorf("task: %+v, %v", info, ok)
+	}
+	if _, ok := m.describeService("nope"); ok {
+		t.Error("unknown service: want !ok")
+	}
+}
@@ -0,0 +1,126 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// EnvOptions controls `floppy env`.
+type EnvOptions struct {
+	Format    string // "dotenv" (default), "json" or "export"
+	NoInherit bool   // leave out variables inherited from the OS environment
+	Reveal    bool   // print secret values instead of masking them
+}
+
+// Env prints the environment the service would be started with. Secret
+// values are masked unless opts.Reveal is set.
+func (m *Manager) Env(name string, opts EnvOptions) error {
+	svc, ok := m.Config.Services[name]
+	if !ok {
+		return fmt.Errorf("service '%s' not found", name)
+	}
+	env := m.serviceEnv(name, svc)
+	if opts.NoInherit {
+		env = m.serviceVars(name, svc)
+	}
+	if !opts.Reveal {
+		secrets := m.secrets()
+		for i, kv := range env {
+			env[i] = secrets.maskVar(kv)
+		}
+	}
+	return writeEnv(os.Stdout, envMap(env), opts.Format)
+}
+
+// Shell opens an interactive shell in the service directory with the
+// environment the service would be started with.
+func (m *Manager) Shell(name string) error {
+	svc, ok := m.Config.Services[name]
+	if !ok {
+		return fmt.Errorf("service '%s' not found", name)
+	}
+	dir := servicePath(m.Root, name, svc.Path)
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("directory not found for %s: %s", name, dir)
+	}
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	fmt.Printf("🐚 %s shell in %s (exit to return)\n", name, dir)
+	cmd := exec.Command(shell)
+	cmd.Dir = dir
+	cmd.Env = append(m.serviceEnv(name, svc), "FLOPPY_SERVICE="+name)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// The shell's exit status reflects the last command typed; not a failure.
+		return nil
+	}
+	return err
+}
+
+// envMap turns KEY=VALUE entries into a map; later entries win, as they do
+// for exec.
+func envMap(env []string) map[string]string {
+	out := make(map[string]string, len(env))
+	for _, kv := range env {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func writeEnv(w io.Writer, env map[string]string, format string) error {
+	keys := stableKeys(env)
+	switch format {
+	case "", "dotenv":
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s=%s\n", k, dotenvQuote(env[k]))
+		}
+	case "export":
+		for _, k := range keys {
+			if !isEnvName(k) {
+				continue // e.g. exported bash functions; not assignable in sh
+			}
+			fmt.Fprintf(w, "export %s=%s\n", k, shellQuote(env[k]))
+		}
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(env)
+	default:
+		return fmt.Errorf("unknown format %q (use dotenv, json or export)", format)
+	}
+	return nil
+}
+
+// dotenvQuote double-quotes values that dotenv parsers would otherwise split
+// or interpret.
+func dotenvQuote(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\n\"'#$\\`=") {
+		return v
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, `$`, `\$`)
+	return `"` + r.Replace(v) + `"`
+}
+
+func isEnvName(k string) bool {
+	for i, r := range k {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return k != ""
+}
@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_envMap(t *testing.T) {
+	env := envMap([]string{"A=1", "B=x=y", "A=2", "bogus", "=nokey"})
+	if len(env) != 2 || env["A"] != "2" || env["B"] != "x=y" {
+		t.Errorf("envMap = %v", env)
+	}
+}
+
+func Test_writeEnv(t *testing.T) {
+	env := map[string]string{
+		"PORT":          "8000",
+		"DSN":           "postgres://u:p w@h/db",
+		"QUOTE":         `it's "x"`,
+		"BASH_FUNC_f%%": "() { :; }",
+	}
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"dotenv", []string{`DSN="postgres://u:p w@h/db"`, `PORT=8000`, `QUOTE="it's \"x\""`}},
+		{"export", []string{`export DSN='postgres://u:p w@h/db'`, `export PORT=8000`, `export QUOTE='it'"'"'s "x"'`}},
+		{"json", []string{`"PORT": "8000"`}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeEnv(&buf, env, tt.format); err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		out := buf.String()
+		for _, line := range tt.want {
+			if !strings.Contains(out, line) {
+				t.Errorf("%s output missing %q:\n%s", tt.format, line, out)
+			}
+		}
+		if tt.format == "export" && strings.Contains(out, "BASH_FUNC") {
+			t.Errorf("export should skip non-identifier keys:\n%s", out)
+		}
+	}
+	if err := writeEnv(&bytes.Buffer{}, env, "yaml"); err == nil {
+		t.Error("unknown format: want error")
+	}
+}
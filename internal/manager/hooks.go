@@ -0,0 +1,140 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"floppy-go/internal/config"
+	"floppy-go/internal/tui"
+)
+
+// Lifecycle hook stages, also used as the yaml keys under hooks.
+const (
+	hookPreStart  = "pre_start"
+	hookPostStart = "post_start"
+	hookPreStop   = "pre_stop"
+	hookPostStop  = "post_stop"
+)
+
+// Default hook timeouts. Start hooks often install dependencies; stop hooks
+// hold up stop and restart, so they get less.
+const (
+	startHookTimeout = 10 * time.Minute
+	stopHookTimeout  = time.Minute
+)
+
+// hookTimeout is how long a hook for stage may run.
+func hookTimeout(h *config.HooksConfig, stage string) time.Duration {
+	if h != nil && h.Timeout > 0 {
+		return h.Timeout
+	}
+	if stage == hookPreStop || stage == hookPostStop {
+		return stopHookTimeout
+	}
+	return startHookTimeout
+}
+
+// hookCommand returns the shell command configured for stage, or "".
+func hookCommand(h *config.HooksConfig, stage string) string {
+	if h == nil {
+		return ""
+	}
+	switch stage {
+	case hookPreStart:
+		return h.PreStart
+	case hookPostStart:
+		return h.PostStart
+	case hookPreStop:
+		return h.PreStop
+	case hookPostStop:
+		return h.PostStop
+	}
+	return ""
+}
+
+// runHook runs the service's hook for stage with `sh -c` in the service
+// directory and the service's environment, passing each output line to out.
+// A failing hook returns an error unless hooks.on_failure is "warn", in which
+// case the failure is reported through out and nil is returned.
+func (m *Manager) runHook(name string, svc config.ServiceDef, stage string, out func(string)) error {
+	command := strings.TrimSpace(hookCommand(svc.Hooks, stage))
+	if command == "" {
+		return nil
+	}
+	policy := svc.Hooks.OnFailure
+	switch policy {
+	case "", "abort", "warn":
+	default:
+		return fmt.Errorf("%s: unknown hooks.on_failure %q (use abort or warn)", name, policy)
+	}
+
+	timeout := hookTimeout(svc.Hooks, stage)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = servicePath(m.Root, name, svc.Path)
+	cmd.Env = m.serviceEnv(name, svc)
+	// Kill the whole hook, not just sh, when it times out.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	cmd.WaitDelay = time.Second
+	w := &lineWriter{fn: func(line string) { out(m.secrets().redact(line)) }}
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err := cmd.Run()
+	w.Flush()
+	if err == nil {
+		return nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	if policy == "warn" {
+		out(fmt.Sprintf("⚠️  %s hook failed (continuing): %v", stage, err))
+		return nil
+	}
+	return fmt.Errorf("%s hook failed: %w", stage, err)
+}
+
+// hookLogger routes hook output to the TUI log stream under the service name,
+// or to stdout when there is no TUI.
+func hookLogger(name, stage string, logCh chan<- tui.LogLine) func(string) {
+	prefix := "[" + stage + "] "
+	if logCh == nil {
+		return func(line string) { fmt.Printf("%s %s%s\n", name, prefix, line) }
+	}
+	return func(line string) { logCh <- tui.LogLine{Service: name, Text: prefix + line} }
+}
+
+// lineWriter splits written bytes into lines and passes each to fn.
+type lineWriter struct {
+	fn  func(string)
+	buf []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.fn(strings.TrimRight(string(w.buf[:i]), "\r"))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush emits any trailing partial line.
+func (w *lineWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.fn(strings.TrimRight(string(w.buf), "\r"))
+		w.buf = nil
+	}
+}
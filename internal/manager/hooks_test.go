@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"floppy-go/internal/config"
+)
+
+func Test_lineWriter(t *testing.T) {
+	var lines []string
+	w := &lineWriter{fn: func(s string) { lines = append(lines, s) }}
+	_, _ = w.Write([]byte("one\r\ntw"))
+	_, _ = w.Write([]byte("o\nthree"))
+	w.Flush()
+	want := []string{"one", "two", "three"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
+
+func Test_runHook(t *testing.T) {
+	dir := t.TempDir()
+	m := &Manager{Root: dir, Config: &config.Config{Env: map[string]any{"GREETING": "hi"}}}
+	svc := config.ServiceDef{Path: ".", Hooks: &config.HooksConfig{
+		PreStart: "echo $GREETING from $(basename $PWD)",
+		PreStop:  "echo failing; exit 3",
+	}}
+
+	var out []string
+	log := func(s string) { out = append(out, s) }
+	if err := m.runHook("api", svc, hookPreStart, log); err != nil {
+		t.Fatalf("pre_start: %v", err)
+	}
+	if len(out) != 1 || !strings.HasPrefix(out[0], "hi from ") {
+		t.Errorf("pre_start output = %q", out)
+	}
+
+	if err := m.runHook("api", svc, hookPreStop, log); err == nil {
+		t.Error("pre_stop: want error under the default abort policy")
+	}
+
+	svc.Hooks.OnFailure = "warn"
+	out = nil
+	if err := m.runHook("api", svc, hookPreStop, log); err != nil {
+		t.Errorf("pre_stop with warn: %v", err)
+	}
+	if len(out) != 2 || out[0] != "failing" {
+		t.Errorf("pre_stop output = %q", out)
+	}
+
+	if err := m.runHook("api", svc, hookPostStop, log); err != nil {
+		t.Errorf("unset hook: %v", err)
+	}
+}
+
+func Test_runHook_timeout(t *testing.T) {
+	m := &Manager{Root: t.TempDir(), Config: &config.Config{}}
+	svc := config.ServiceDef{Path: ".", Hooks: &config.HooksConfig{
+		PreStop: "sleep 30 & sleep 30",
+		Timeout: 200 * time.Millisecond,
+	}}
+	start := time.Now()
+	err := m.runHook("api", svc, hookPreStop, func(string) {})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 200ms") {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("hook ran for %s after its timeout", elapsed)
+	}
+	if got := hookTimeout(nil, hookPreStart); got != startHookTimeout {
+		t.Errorf("default pre_start timeout = %s", got)
+	}
+	if got := hookTimeout(nil, hookPostStop); got != stopHookTimeout {
+		t.Errorf("default post_stop timeout = %s", got)
+	}
+}
@@ -0,0 +1,182 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync/atomic"
+	"time"
+
+	"floppy-go/internal/config"
+	"floppy-go/internal/procstats"
+)
+
+// serviceLimits is the parsed form of config.LimitsConfig.
+type serviceLimits struct {
+	MemoryBytes int64
+	CPU         float64
+	NoFile      uint64
+}
+
+func (l serviceLimits) empty() bool {
+	return l.MemoryBytes == 0 && l.CPU == 0 && l.NoFile == 0
+}
+
+func parseLimits(cfg *config.LimitsConfig) (serviceLimits, error) {
+	var out serviceLimits
+	if cfg == nil {
+		return out, nil
+	}
+	if cfg.Memory != "" {
+		n, err := config.ParseSize(cfg.Memory)
+		if err != nil {
+			return out, fmt.Errorf("limits.memory: %w", err)
+		}
+		out.MemoryBytes = n
+	}
+	if cfg.CPU < 0 {
+		return out, fmt.Errorf("limits.cpu must be positive, got %v", cfg.CPU)
+	}
+	out.CPU = cfg.CPU
+	out.NoFile = cfg.NoFile
+	return out, nil
+}
+
+// errNoCgroup means no delegated cgroup v2 subtree is available to us.
+var errNoCgroup = errors.New("cgroup v2 delegation not available")
+
+// memoryWatchInterval is how often the RSS watchdog polls a service that has
+// a memory limit but no cgroup to enforce it.
+const memoryWatchInterval = 2 * time.Second
+
+// limitHandle tracks how limits are enforced for one running process.
+type limitHandle struct {
+	limits serviceLimits
+	cgroup *serviceCgroup
+	// pollMemory starts the RSS watchdog for a memory limit without a cgroup.
+	pollMemory bool
+	// oom is set when the RSS watchdog killed the process group.
+	oom  atomic.Bool
+	done chan struct{}
+}
+
+// applyLimits prepares cmd so it starts inside a per-service cgroup when the
+// service has memory or CPU limits and delegation is available, and with the
+// open-files limit set. Warnings are returned for limits that cannot be
+// enforced as configured: without a cgroup the memory limit is only enforced
+// by polling RSS, which needs /proc and a floppy that stays running.
+func (m *Manager) applyLimits(name string, svc config.ServiceDef, cmd *exec.Cmd, detached bool) (*limitHandle, []string, error) {
+	lim, err := parseLimits(svc.Limits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if lim.empty() {
+		return nil, nil, nil
+	}
+	h := &limitHandle{limits: lim, done: make(chan struct{})}
+	var warnings []string
+	if lim.MemoryBytes > 0 || lim.CPU > 0 {
+		cg, err := createCgroup(name, lim)
+		if err != nil {
+			if lim.MemoryBytes > 0 {
+				switch {
+				case detached:
+					warnings = append(warnings, fmt.Sprintf("%s: %v; memory limit not enforced (up -d does not stay running to poll RSS)", name, err))
+				case !procstats.Supported():
+					warnings = append(warnings, fmt.Sprintf("%s: %v; memory limit not enforced (polling RSS needs /proc)", name, err))
+				default:
+					warnings = append(warnings, fmt.Sprintf("%s: %v; memory limit enforced by polling RSS", name, err))
+					h.pollMemory = true
+				}
+			}
+			if lim.CPU > 0 {
+				warnings = append(warnings, fmt.Sprintf("%s: %v; cpu limit not enforced", name, err))
+			}
+		} else {
+			h.cgroup = cg
+		}
+	}
+	h.attach(cmd)
+	return h, warnings, nil
+}
+
+// attach places cmd in the service cgroup, if there is one, and makes it
+// start with the open-files limit.
+func (h *limitHandle) attach(cmd *exec.Cmd) {
+	if h == nil {
+		return
+	}
+	if h.cgroup != nil {
+		h.cgroup.attach(cmd)
+	}
+	if h.limits.NoFile > 0 {
+		withNoFile(cmd, h.limits.NoFile)
+	}
+}
+
+// withNoFile runs cmd through sh, which sets RLIMIT_NOFILE before exec'ing
+// the command. Setting it on the process after start would miss anything it
+// had already forked (poetry -> python, bun -> vite). When the limit cannot
+// be set the service starts anyway and the shell's error is in its log.
+func withNoFile(cmd *exec.Cmd, n uint64) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		sh = "/bin/sh"
+	}
+	script := fmt.Sprintf(`ulimit -n %d || echo "floppy: could not set nofile=%d" >&2; exec "$@"`, n, n)
+	// The word after the script is sh's $0; "$@" is the command and its args.
+	args := append([]string{"sh", "-c", script, cmd.Args[0], cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = sh
+	cmd.Args = args
+}
+
+// started finishes applying limits once the process exists: without a
+// cgroup, an RSS watchdog is started for the memory limit.
+func (h *limitHandle) started(pid int) {
+	if h == nil {
+		return
+	}
+	if h.cgroup != nil {
+		h.cgroup.started()
+	}
+	if h.pollMemory {
+		go h.watchMemory(pid)
+	}
+}
+
+// watchMemory kills the process group once its RSS passes the memory limit.
+func (h *limitHandle) watchMemory(pgid int) {
+	ticker := time.NewTicker(memoryWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-ticker.C:
+			u, err := procstats.Group(pgid)
+			if err != nil {
+				return
+			}
+			if u.RSSBytes > h.limits.MemoryBytes {
+				h.oom.Store(true)
+				_ = killProcess(pgid)
+				return
+			}
+		}
+	}
+}
+
+// exited stops the watchdog, reports whether the process was killed for
+// exceeding its memory limit, and removes the cgroup.
+func (h *limitHandle) exited() bool {
+	if h == nil {
+		return false
+	}
+	close(h.done)
+	oom := h.oom.Load()
+	if h.cgroup != nil {
+		oom = oom || h.cgroup.oomKilled()
+		_ = h.cgroup.remove()
+	}
+	return oom
+}
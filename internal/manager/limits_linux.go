@@ -0,0 +1,175 @@
+//go:build linux
+
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const cgroupMount = "/sys/fs/cgroup"
+
+// serviceCgroup is a cgroup v2 directory created for one service.
+type serviceCgroup struct {
+	path string
+	fd   int
+}
+
+// cgroupRoot returns a cgroup v2 directory we may create children in and
+// whose children get the memory and cpu controllers. FLOPPY_CGROUP_ROOT
+// takes precedence (e.g. a unit started with Delegate=yes); otherwise the
+// parent of our own cgroup is used only when systemd marked it delegated.
+// Being able to write to it is not enough: as root, every slice is writable.
+func cgroupRoot() (string, error) {
+	if explicit := strings.TrimSpace(os.Getenv("FLOPPY_CGROUP_ROOT")); explicit != "" {
+		if usableCgroupRoot(explicit) {
+			return explicit, nil
+		}
+		return "", errNoCgroup
+	}
+	if own := ownCgroup(); own != "" {
+		dir := filepath.Dir(filepath.Join(cgroupMount, own))
+		if delegatedCgroup(dir) && usableCgroupRoot(dir) {
+			return dir, nil
+		}
+	}
+	return "", errNoCgroup
+}
+
+// delegatedCgroup reports whether systemd delegated dir (Delegate=yes),
+// which it records in the trusted.delegate or user.delegate xattr.
+func delegatedCgroup(dir string) bool {
+	buf := make([]byte, 8)
+	for _, attr := range []string{"trusted.delegate", "user.delegate"} {
+		if n, err := unix.Getxattr(dir, attr, buf); err == nil && string(buf[:n]) == "1" {
+			return true
+		}
+	}
+	return false
+}
+
+// ownCgroup returns our cgroup v2 path from /proc/self/cgroup ("0::/path").
+func ownCgroup() string {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(line, "0::"); ok {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return ""
+}
+
+func usableCgroupRoot(dir string) bool {
+	if syscall.Access(dir, unix.W_OK) != nil {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "cgroup.subtree_control"))
+	if err != nil {
+		return false
+	}
+	controllers := strings.Fields(string(data))
+	hasMemory, hasCPU := false, false
+	for _, c := range controllers {
+		hasMemory = hasMemory || c == "memory"
+		hasCPU = hasCPU || c == "cpu"
+	}
+	return hasMemory && hasCPU
+}
+
+// cgroupPath returns where the cgroup for service name lives, or "" when no
+// delegated cgroup root is available.
+func cgroupPath(name string) string {
+	root, err := cgroupRoot()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(root, "floppy-"+name)
+}
+
+func createCgroup(name string, lim serviceLimits) (*serviceCgroup, error) {
+	path := cgroupPath(name)
+	if path == "" {
+		return nil, errNoCgroup
+	}
+	if err := os.Mkdir(path, 0o755); err != nil && !os.IsExist(err) {
+		return nil, err
+	}
+	fail := func(err error) (*serviceCgroup, error) {
+		_ = os.Remove(path)
+		return nil, err
+	}
+	if lim.MemoryBytes > 0 {
+		if err := writeCgroupFile(path, "memory.max", strconv.FormatInt(lim.MemoryBytes, 10)); err != nil {
+			return fail(err)
+		}
+		// Kill the whole service rather than one worker when the limit is hit.
+		_ = writeCgroupFile(path, "memory.oom.group", "1")
+	}
+	if lim.CPU > 0 {
+		const period = 100000
+		quota := int64(lim.CPU * period)
+		if err := writeCgroupFile(path, "cpu.max", fmt.Sprintf("%d %d", quota, period)); err != nil {
+			return fail(err)
+		}
+	}
+	fd, err := syscall.Open(path, syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return fail(err)
+	}
+	return &serviceCgroup{path: path, fd: fd}, nil
+}
+
+func writeCgroupFile(dir, file, value string) error {
+	return os.WriteFile(filepath.Join(dir, file), []byte(value), 0o644)
+}
+
+// attach makes cmd start directly inside the cgroup (CLONE_INTO_CGROUP), so
+// nothing the service forks can escape the limits.
+func (c *serviceCgroup) attach(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = c.fd
+}
+
+func (c *serviceCgroup) started() {
+	if c.fd >= 0 {
+		_ = syscall.Close(c.fd)
+		c.fd = -1
+	}
+}
+
+// oomKilled reports whether the kernel OOM-killed anything in the cgroup.
+func (c *serviceCgroup) oomKilled() bool {
+	f, err := os.Open(filepath.Join(c.path, "memory.events"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			n, _ := strconv.Atoi(fields[1])
+			return n > 0
+		}
+	}
+	return false
+}
+
+func (c *serviceCgroup) remove() error {
+	c.started()
+	return os.Remove(c.path)
+}
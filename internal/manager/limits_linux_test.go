@@ -0,0 +1,27 @@
+//go:build linux
+
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_cgroupRoot(t *testing.T) {
+	dir := t.TempDir()
+	if delegatedCgroup(dir) {
+		t.Errorf("%s has no delegate xattr", dir)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte("cpu memory pids\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FLOPPY_CGROUP_ROOT", dir)
+	if root, err := cgroupRoot(); err != nil || root != dir {
+		t.Errorf("cgroupRoot = %q, %v; want FLOPPY_CGROUP_ROOT", root, err)
+	}
+	t.Setenv("FLOPPY_CGROUP_ROOT", t.TempDir()) // no controllers enabled
+	if root, err := cgroupRoot(); err == nil {
+		t.Errorf("cgroupRoot = %q, want an error for an unusable FLOPPY_CGROUP_ROOT", root)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package manager
+
+import "os/exec"
+
+// serviceCgroup is only implemented on Linux.
+type serviceCgroup struct{}
+
+func cgroupPath(name string) string { return "" }
+
+func createCgroup(name string, lim serviceLimits) (*serviceCgroup, error) {
+	return nil, errNoCgroup
+}
+
+func (c *serviceCgroup) attach(cmd *exec.Cmd) {}
+func (c *serviceCgroup) started()             {}
+func (c *serviceCgroup) oomKilled() bool      { return false }
+func (c *serviceCgroup) remove() error        { return nil }
@@ -0,0 +1,288 @@
+package manager
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"floppy-go/internal/tui"
+)
+
+// maxServiceLogBytes is the size at which a persisted log is rotated to a
+// single ".1" backup.
+const maxServiceLogBytes = 10 << 20
+
+// serviceLogFile is the persisted output of a service: one line per log
+// line, "RFC3339Nano-UTC text". The TUI shows the same receive times.
+func serviceLogFile(name string) string {
+	return filepath.Join(serviceLogDir(name), "output.log")
+}
+
+type serviceLog struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	size int64
+}
+
+var (
+	serviceLogsMu sync.Mutex
+	serviceLogs   = map[string]*serviceLog{}
+)
+
+// persistLine appends a line to the service's persisted log. Failures are
+// ignored: persistence must never block a service's output.
+func persistLine(name string, at time.Time, text string) {
+	serviceLogsMu.Lock()
+	l, ok := serviceLogs[name]
+	if !ok {
+		l = &serviceLog{path: serviceLogFile(name)}
+		serviceLogs[name] = l
+	}
+	serviceLogsMu.Unlock()
+	l.write(at, text)
+}
+
+func (l *serviceLog) write(at time.Time, text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+			return
+		}
+		f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return
+		}
+		info, _ := f.Stat()
+		l.f = f
+		if info != nil {
+			l.size = info.Size()
+		}
+	}
+	line := formatLogLine(at, text)
+	n, _ := io.WriteString(l.f, line)
+	l.size += int64(n)
+	if l.size >= maxServiceLogBytes {
+		l.f.Close()
+		l.f = nil
+		_ = os.Rename(l.path, l.path+".1")
+	}
+}
+
+// closeServiceLogs closes every open persisted log.
+func closeServiceLogs() {
+	serviceLogsMu.Lock()
+	defer serviceLogsMu.Unlock()
+	for name, l := range serviceLogs {
+		l.mu.Lock()
+		if l.f != nil {
+			l.f.Close()
+		}
+		l.mu.Unlock()
+		delete(serviceLogs, name)
+	}
+}
+
+func formatLogLine(at time.Time, text string) string {
+	return at.UTC().Format(time.RFC3339Nano) + " " + text + "\n"
+}
+
+// parseLogLine splits a persisted line into its receive time and text.
+func parseLogLine(line string) (time.Time, string, bool) {
+	stamp, text, ok := strings.Cut(strings.TrimRight(line, "\n"), " ")
+	if !ok {
+		return time.Time{}, "", false
+	}
+	at, err := time.Parse(time.RFC3339Nano, stamp)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return at, text, true
+}
+
+// emitLine redacts secrets from a line of service output, timestamps and
+// persists it, checks it against the notify patterns and sends it to the
+// TUI.
+func (m *Manager) emitLine(service, line string, logCh chan<- tui.LogLine) {
+	text := m.secrets().redact(strings.TrimRight(line, "\r\n"))
+	at := time.Now()
+	persistLine(service, at, text)
+	m.notifier.line(service, text)
+	logCh <- tui.LogLine{Service: service, Text: text, Time: at}
+}
+
+// PersistOutput persists service output read from r until EOF. `up -d`
+// pipes each service's output to a `floppy log-writer` process running it,
+// so detached services are logged like those under the TUI.
+func (m *Manager) PersistOutput(service string, r io.Reader) error {
+	defer closeServiceLogs()
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			persistLine(service, time.Now(), m.secrets().redact(strings.TrimRight(line, "\r\n")))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// startLogWriter starts a `floppy log-writer` process for a detached
+// service and returns the pipe the service should write its output to. The
+// writer outlives `up -d` and exits when the service closes the pipe.
+func (m *Manager) startLogWriter(service string) (*os.File, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	writer := exec.Command(exe, "log-writer", "--file", m.ConfigPath, service)
+	writer.Stdin = r
+	writer.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := writer.Start(); err != nil {
+		w.Close()
+		return nil, err
+	}
+	_ = writer.Process.Release()
+	return w, nil
+}
+
+// LogsOptions controls `floppy logs`.
+type LogsOptions struct {
+	Follow     bool
+	Tail       int       // last N lines; 0 or less shows all
+	Since      time.Time // zero shows everything
+	Timestamps bool
+	Out        io.Writer // defaults to stdout
+}
+
+// ParseSince accepts a duration before now ("10m", "1h30m") or an RFC 3339
+// time.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration like 10m or an RFC 3339 time", value)
+}
+
+// Logs prints the persisted output of a service as recorded by `floppy up`.
+func (m *Manager) Logs(service string, opts LogsOptions) error {
+	if _, ok := m.Config.Services[service]; !ok {
+		return fmt.Errorf("unknown service: %s", service)
+	}
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	path := serviceLogFile(service)
+	lines := []string{}
+	found := false
+	for _, p := range []string{path + ".1", path} {
+		f, err := os.Open(p)
+		if err != nil {
+			continue
+		}
+		found = true
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if line, ok := formatLogOutput(scanner.Text(), opts); ok {
+				lines = append(lines, line)
+			}
+		}
+		f.Close()
+	}
+	if !found && !opts.Follow {
+		return fmt.Errorf("no logs recorded for %s yet (logs are kept for services started with `floppy up`)", service)
+	}
+	if opts.Tail > 0 && len(lines) > opts.Tail {
+		lines = lines[len(lines)-opts.Tail:]
+	}
+	for _, line := range lines {
+		fmt.Fprintln(out, line)
+	}
+	if !opts.Follow {
+		return nil
+	}
+	return followLog(path, out, opts)
+}
+
+func formatLogOutput(raw string, opts LogsOptions) (string, bool) {
+	at, text, ok := parseLogLine(raw)
+	if !ok {
+		return raw, opts.Since.IsZero()
+	}
+	if !opts.Since.IsZero() && at.Before(opts.Since) {
+		return "", false
+	}
+	if opts.Timestamps {
+		return at.Local().Format("2006-01-02 15:04:05.000") + " " + text, true
+	}
+	return text, true
+}
+
+// followLog prints lines appended to path until interrupted, reopening it
+// after rotation.
+func followLog(path string, out io.Writer, opts LogsOptions) error {
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+	partial := ""
+	for {
+		time.Sleep(250 * time.Millisecond)
+		info, err := os.Stat(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				offset = 0
+				continue
+			}
+			return err
+		}
+		if info.Size() < offset {
+			offset = 0 // rotated
+		}
+		if info.Size() == offset {
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		buf := make([]byte, info.Size()-offset)
+		n, _ := f.ReadAt(buf, offset)
+		f.Close()
+		offset += int64(n)
+		chunk := partial + string(buf[:n])
+		parts := strings.Split(chunk, "\n")
+		partial = parts[len(parts)-1]
+		for _, raw := range parts[:len(parts)-1] {
+			if line, ok := formatLogOutput(raw, opts); ok {
+				fmt.Fprintln(out, line)
+			}
+		}
+	}
+}
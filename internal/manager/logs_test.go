@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"floppy-go/internal/config"
+	"floppy-go/internal/tui"
+)
+
+func Test_emitLine_persistsReceiveTime(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer closeServiceLogs()
+
+	logCh := make(chan tui.LogLine, 1)
+	new(Manager).emitLine("api", "hello\r\n", logCh)
+	closeServiceLogs()
+	got := <-logCh
+	if got.Text != "hello" || got.Time.IsZero() {
+		t.Fatalf("LogLine = %+v", got)
+	}
+
+	m := &Manager{Config: &config.Config{Services: map[string]config.ServiceDef{"api": {}}}}
+	var out bytes.Buffer
+	if err := m.Logs("api", LogsOptions{Timestamps: true, Out: &out}); err != nil {
+		t.Fatal(err)
+	}
+	want := got.Time.Local().Format("2006-01-02 15:04:05.000") + " hello\n"
+	if out.String() != want {
+		t.Errorf("logs = %q, want %q", out.String(), want)
+	}
+}
+
+func Test_Logs_sinceAndTail(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer closeServiceLogs()
+
+	now := time.Now()
+	for i, ago := range []time.Duration{30 * time.Minute, 20 * time.Minute, 5 * time.Minute, time.Minute} {
+		persistLine("api", now.Add(-ago), strings.Repeat("x", i+1))
+	}
+	closeServiceLogs()
+
+	m := &Manager{Config: &config.Config{Services: map[string]config.ServiceDef{"api": {}, "web": {}}}}
+	since, err := ParseSince("10m", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := m.Logs("api", LogsOptions{Since: since, Out: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "xxx\nxxxx\n" {
+		t.Errorf("--since 10m = %q", out.String())
+	}
+
+	out.Reset()
+	if err := m.Logs("api", LogsOptions{Tail: 1, Out: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "xxxx\n" {
+		t.Errorf("--tail 1 = %q", out.String())
+	}
+
+	if err := m.Logs("web", LogsOptions{Out: &out}); err == nil {
+		t.Error("want error for a service without logs")
+	}
+	if err := m.Logs("nope", LogsOptions{Out: &out}); err == nil {
+		t.Error("want error for an unknown service")
+	}
+	if _, err := ParseSince("yesterday", now); err == nil {
+		t.Error("ParseSince: want error")
+	}
+}
+
+func Test_PersistOutput(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer closeServiceLogs()
+
+	m := &Manager{Config: &config.Config{Services: map[string]config.ServiceDef{"api": {}}}}
+	if err := m.PersistOutput("api", strings.NewReader("started\r\nno newline")); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := m.Logs("api", LogsOptions{Out: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "started\nno newline\n" {
+		t.Errorf("logs = %q", out.String())
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -27,7 +29,26 @@ type Manager struct {
 
 	procMu    sync.Mutex
 	processes map[string]*exec.Cmd
+	exited    map[string]chan struct{} // closed when the tracked process exits
 	statuses  map[string]*ServiceStatus
+	tasks     map[string]*taskRun
+
+	// run holds how Up started services so they can be restarted later.
+	run         runOptions
+	watchPaused atomic.Bool
+	notifier    *notifier
+	stopping    map[string]bool        // services floppy is stopping on purpose
+	restartMu   map[string]*sync.Mutex // one restart per service at a time
+	starts      map[string]int         // processes started per service by this manager
+	secretsOnce sync.Once
+	masker      *secretMasker
+}
+
+type runOptions struct {
+	detached bool
+	noPTY    bool
+	logCh    chan<- tui.LogLine
+	statusCh chan<- tui.StatusUpdate
 }
 
 type ServiceStatus struct {
@@ -45,11 +66,16 @@ func New(cfg *config.Config, configPath string) *Manager {
 		ConfigPath: configPath,
 		Root:       root,
 		processes:  map[string]*exec.Cmd{},
+		exited:     map[string]chan struct{}{},
 		statuses:   map[string]*ServiceStatus{},
+		tasks:      map[string]*taskRun{},
+		stopping:   map[string]bool{},
+		restartMu:  map[string]*sync.Mutex{},
+		starts:     map[string]int{},
 	}
 }
 
-func (m *Manager) Up(services []string, detached bool, force bool, noPTY bool) error {
+func (m *Manager) Up(services []string, detached bool, force bool, noPTY bool, build bool) error {
 	if len(services) == 0 {
 		services = m.Config.ServiceNames()
 	}
@@ -58,10 +84,30 @@ func (m *Manager) Up(services []string, detached bool, force bool, noPTY bool) e
 		return errors.New("no services to start")
 	}
 
+	tasks, err := m.serviceTasks(services)
+	if err != nil {
+		return err
+	}
+	if !detached {
+		if err := m.checkTUIConfig(); err != nil {
+			return err
+		}
+	}
+	buildWarnings := []string{}
+	if build {
+		if err := m.buildServices(services); err != nil {
+			return err
+		}
+	} else {
+		buildWarnings = m.staleBuilds(services)
+	}
 	for _, name := range services {
 		svc := m.Config.Services[name]
 		m.statuses[name] = &ServiceStatus{Name: name, Type: svc.Type, Port: svc.Port, Status: "starting"}
 	}
+	for _, name := range tasks {
+		m.statuses[name] = &ServiceStatus{Name: name, Type: "task", Status: "pending"}
+	}
 
 	if err := m.validatePorts(services, force); err != nil {
 		return err
@@ -79,12 +125,61 @@ func (m *Manager) Up(services []string, detached bool, force bool, noPTY bool) e
 
 	statusCh := make(chan tui.StatusUpdate, 64)
 	logCh := make(chan tui.LogLine, 2048)
+	m.run = runOptions{detached: detached, noPTY: noPTY, logCh: logCh, statusCh: statusCh}
+	if !detached {
+		n, err := newNotifier(m.Config.Notify, os.Stderr, logCh)
+		if err != nil {
+			return err
+		}
+		m.notifier = n
+	}
+	for _, w := range buildWarnings {
+		if detached {
+			fmt.Printf("⚠️  %s\n", w)
+		}
+		logCh <- tui.LogLine{Service: "WARN", Text: w}
+	}
 
-	startFn := func(name string) {
+	startOne := func(name string) {
 		if err := m.startService(name, detached, noPTY, logCh, statusCh); err != nil {
 			statusCh <- tui.StatusUpdate{Name: name, Status: "error"}
 			logCh <- tui.LogLine{Service: "ERROR", Text: fmt.Sprintf("%s: %v", name, err)}
+			m.notifier.crash(name, fmt.Sprintf("failed to start: %v", err))
+			if detached {
+				fmt.Printf("❌ %s: %v\n", name, err)
+			}
+		}
+	}
+	// Services that depend on tasks start once those tasks succeed. In the
+	// TUI that happens in the background so task output shows up live; the
+	// same goes for pre_start hooks, whose output could otherwise fill logCh
+	// before the TUI is there to drain it.
+	startFn := func(name string) {
+		svc := m.Config.Services[name]
+		deps := svc.DependsOn
+		start := func() {
+			if len(deps) > 0 {
+				var taskLog chan<- tui.LogLine = logCh
+				if detached {
+					taskLog = nil
+				}
+				if err := m.runTasks(deps, taskLog, statusCh); err != nil {
+					statusCh <- tui.StatusUpdate{Name: name, Status: "error"}
+					logCh <- tui.LogLine{Service: "ERROR", Text: fmt.Sprintf("%s: not started: %v", name, err)}
+					m.notifier.crash(name, fmt.Sprintf("not started: %v", err))
+					if detached {
+						fmt.Printf("❌ %s not started: %v\n", name, err)
+					}
+					return
+				}
+			}
+			startOne(name)
 		}
+		if detached || (len(deps) == 0 && hookCommand(svc.Hooks, hookPreStart) == "") {
+			start()
+			return
+		}
+		go start()
 	}
 
 	for _, name := range others {
@@ -107,11 +202,37 @@ func (m *Manager) Up(services []string, detached bool, force bool, noPTY bool) e
 		postgresURL = m.Config.Stats.DB.URL
 	}
 	dockerEnabled := m.Config.Stats != nil && m.Config.Stats.Docker != nil && m.Config.Stats.Docker.Enabled
-	model := tui.NewModel(logCh, statusCh, m.snapshotStatuses(), postgresURL, dockerEnabled)
+	tuiConfig := config.TUIConfig{}
+	if m.Config.TUI != nil {
+		tuiConfig = *m.Config.TUI
+	}
+
+	stopWatching := m.watchServices(services)
+	defer stopWatching()
+	requests := make(chan tui.Request, 8)
+	defer close(requests)
+	go m.handleRequests(requests)
+
+	model := tui.NewModel(logCh, statusCh, m.snapshotStatuses(), tui.Options{
+		PostgresURL:   postgresURL,
+		DockerEnabled: dockerEnabled,
+		Requests:      requests,
+		Watching:      m.hasWatchedService(services),
+		Scrollback:    tuiConfig.Scrollback,
+		SpillDir:      spillDir(),
+		Describe:      m.describeService,
+		Keys:          tuiConfig.Keys,
+		Theme:         tuiConfig.Theme,
+		Colors:        tuiConfig.Colors,
+	})
+	defer model.Close()
+	defer closeServiceLogs()
 	p := tui.NewProgram(model)
 	if err := p.Start(); err != nil {
 		return err
 	}
+	stopWatching()
+	m.notifier.close()
 	if model.Interrupted() {
 		m.Stop(services, false)
 	}
@@ -119,6 +240,8 @@ func (m *Manager) Up(services []string, detached bool, force bool, noPTY bool) e
 }
 
 func (m *Manager) Stop(services []string, forcePortKill bool) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
 	state := loadProcessState()
 	detected := DetectRunningServices(m.Config, m.Root)
 
@@ -173,7 +296,7 @@ func (m *Manager) Stop(services []string, forcePortKill bool) error {
 					continue
 				}
 			} else {
-				if err := killProcess(entry.PID); err != nil {
+				if err := m.stopWithHooks(name, svc, func() error { return killProcess(entry.PID) }); err != nil {
 					fmt.Printf("Failed to stop %s (tracked PID %d): %v\n", name, entry.PID, err)
 				} else {
 					delete(state.Entries, name)
@@ -191,7 +314,7 @@ func (m *Manager) Stop(services []string, forcePortKill bool) error {
 		}
 
 		if svc.Port > 0 {
-			if err := killPort(svc.Port); err != nil {
+			if err := m.stopWithHooks(name, svc, func() error { return killPort(svc.Port) }); err != nil {
 				fmt.Printf("Failed to stop %s (port %d): %v\n", name, svc.Port, err)
 			} else {
 				delete(state.Entries, name)
@@ -204,7 +327,7 @@ func (m *Manager) Stop(services []string, forcePortKill bool) error {
 			fmt.Printf("Skipping %s: no running process found for fallback\n", name)
 			continue
 		}
-		if err := killProcess(info.PID); err != nil {
+		if err := m.stopWithHooks(name, svc, func() error { return killProcess(info.PID) }); err != nil {
 			fmt.Printf("Failed to stop %s (PID %d): %v\n", name, info.PID, err)
 		} else {
 			delete(state.Entries, name)
@@ -219,36 +342,20 @@ func (m *Manager) Stop(services []string, forcePortKill bool) error {
 	return nil
 }
 
-func (m *Manager) Ps(quiet bool) {
-	detected := DetectRunningServices(m.Config, m.Root)
-	if len(detected) == 0 {
-		fmt.Println("No services running")
-		return
-	}
-
-	if quiet {
-		names := make([]string, 0, len(detected))
-		for name := range detected {
-			names = append(names, name)
-		}
-		sort.Strings(names)
-		for _, name := range names {
-			fmt.Println(name)
-		}
-		return
+// stopWithHooks runs the pre_stop hook, kill and then the post_stop hook. A
+// failing pre_stop hook (under the abort policy) leaves the service running;
+// a failing post_stop hook is only reported since the service is already down.
+func (m *Manager) stopWithHooks(name string, svc config.ServiceDef, kill func() error) error {
+	if err := m.runHook(name, svc, hookPreStop, hookLogger(name, hookPreStop, nil)); err != nil {
+		return err
 	}
-
-	fmt.Printf("%-24s %-8s %-8s %-6s\n", "SERVICE", "STATUS", "PORT", "PID")
-	fmt.Println(strings.Repeat("-", 52))
-	keys := make([]string, 0, len(detected))
-	for name := range detected {
-		keys = append(keys, name)
+	if err := kill(); err != nil {
+		return err
 	}
-	sort.Strings(keys)
-	for _, name := range keys {
-		info := detected[name]
-		fmt.Printf("%-24s %-8s %-8d %-6d\n", name, "RUN", info.Port, info.PID)
+	if err := m.runHook(name, svc, hookPostStop, hookLogger(name, hookPostStop, nil)); err != nil {
+		fmt.Printf("Warning: %s: %v\n", name, err)
 	}
+	return nil
 }
 
 func (m *Manager) List(grouped bool) {
@@ -261,6 +368,12 @@ func (m *Manager) List(grouped bool) {
 			}
 			fmt.Printf("  - %s (%s, port: %s)\n", name, svc.Type, port)
 		}
+		if len(m.Config.Tasks) > 0 {
+			fmt.Println("\nAvailable tasks:")
+			for _, name := range stableKeys(m.Config.Tasks) {
+				fmt.Printf("  - %s: %s\n", name, m.Config.Tasks[name].Command)
+			}
+		}
 		fmt.Println("\nAvailable bundles:")
 		for name, services := range m.Config.Bundles {
 			fmt.Printf("  - %s: %s\n", name, strings.Join(services, ", "))
@@ -297,6 +410,20 @@ func (m *Manager) List(grouped bool) {
 		}
 	}
 
+	if len(m.Config.Tasks) > 0 {
+		fmt.Printf("\nTasks (%d):\n", len(m.Config.Tasks))
+		fmt.Printf("%-24s %-24s %s\n", "Task", "Depends on", "Command")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, name := range stableKeys(m.Config.Tasks) {
+			task := m.Config.Tasks[name]
+			deps := strings.Join(task.DependsOn, ",")
+			if deps == "" {
+				deps = "-"
+			}
+			fmt.Printf("%-24s %-24s %s\n", name, deps, task.Command)
+		}
+	}
+
 	if len(m.Config.Bundles) > 0 {
 		fmt.Println("\nBundles:")
 		keys := make([]string, 0, len(m.Config.Bundles))
@@ -533,18 +660,19 @@ func (m *Manager) Setup() {
 		install.Env = env
 		install.Stdout = os.Stdout
 		install.Stderr = os.Stderr
-		_ = install.Run()
+		if err := install.Run(); err == nil && svc.Build == "" {
+			// Same as the default build step, so `up` need not ask for a rebuild.
+			if hash, err := hashBuildInputs(path, buildInputs(svc)); err == nil {
+				m.recordBuild(name, hash)
+			}
+		}
 	}
 
 	m.createDatabases(false) // all services in full setup
 
-	for name, svc := range m.Config.Services {
-		if !isPythonType(svc.Type) {
-			continue
-		}
-		path := servicePath(m.Root, name, svc.Path)
+	for _, path := range m.migrationDirs() {
 		if _, err := os.Stat(path); err != nil {
-			fmt.Printf("Directory not found for %s, skipping migrations.\n", name)
+			fmt.Printf("Directory not found: %s, skipping migrations.\n", path)
 			continue
 		}
 		migr := exec.Command(resolveTool("poetry", "FLOPPY_POETRY"), "run", "alembic", "upgrade", "heads")
@@ -554,34 +682,58 @@ func (m *Manager) Setup() {
 		_ = migr.Run()
 	}
 
+	if err := m.runSetupTasks(); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+
 	fmt.Println("Setup complete!")
 }
 
+// migrationDirs are the directories Setup runs `alembic upgrade heads` in:
+// one per directory of a Python service, skipping directories where a
+// service sets `migrate: false` (its migrations run as a setup task).
+func (m *Manager) migrationDirs() []string {
+	names := []string{}
+	for name, svc := range m.Config.Services {
+		if isPythonType(svc.Type) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	skip := map[string]bool{}
+	for _, name := range names {
+		svc := m.Config.Services[name]
+		if svc.Migrate != nil && !*svc.Migrate {
+			skip[servicePath(m.Root, name, svc.Path)] = true
+		}
+	}
+	dirs := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		svc := m.Config.Services[name]
+		path := servicePath(m.Root, name, svc.Path)
+		if !skip[path] && !seen[path] {
+			seen[path] = true
+			dirs = append(dirs, path)
+		}
+	}
+	return dirs
+}
+
 func (m *Manager) SetupDB() {
 	m.createDatabases(true) // api services only
 	fmt.Println("Database setup complete!")
 }
 
 func (m *Manager) createDatabases(apiOnly bool) {
-	dbUser := valueOr(m.Config.Env["DB_USER"], "postgres")
-	dbPassword := valueOr(m.Config.Env["DB_PASSWORD"], "postgres")
-	dbHost := valueOr(m.Config.Env["DB_HOST"], "localhost")
-	pathEnv := os.Getenv("PATH")
-
 	for name, svc := range m.Config.Services {
 		if apiOnly && svc.Type != "api" {
 			continue
 		}
-		dbs := []string{name, fmt.Sprintf("%s_test", name)}
-		for _, db := range dbs {
-			query := fmt.Sprintf("SELECT 1 FROM pg_database WHERE datname = '%s'", db)
-			check := exec.Command("psql", "-U", dbUser, "-h", dbHost, "-tAc", query)
-			check.Env = append(os.Environ(), fmt.Sprintf("PGPASSWORD=%s", dbPassword), fmt.Sprintf("PATH=%s", pathEnv))
-			out, _ := check.Output()
-			if !strings.Contains(string(out), "1") {
+		for _, db := range serviceDatabases(name) {
+			if !m.databaseExists(db) {
 				fmt.Printf("Creating database: %s\n", db)
-				create := exec.Command("psql", "-U", dbUser, "-h", dbHost, "-c", fmt.Sprintf("CREATE DATABASE \"%s\";", db))
-				create.Env = append(os.Environ(), fmt.Sprintf("PGPASSWORD=%s", dbPassword), fmt.Sprintf("PATH=%s", pathEnv))
+				create := m.psqlCommand("-c", fmt.Sprintf("CREATE DATABASE \"%s\";", db))
 				create.Stdout = io.Discard
 				create.Stderr = os.Stderr
 				_ = create.Run()
@@ -590,9 +742,25 @@ func (m *Manager) createDatabases(apiOnly bool) {
 	}
 }
 
-func (m *Manager) Logs(service string, follow bool, tail int) {
-	fmt.Printf("Logs for %s (follow=%v, tail=%d)\n", service, follow, tail)
-	fmt.Println("Log functionality would be implemented here")
+// serviceDatabases are the databases createDatabases makes for a service.
+func serviceDatabases(name string) []string {
+	return []string{name, fmt.Sprintf("%s_test", name)}
+}
+
+func (m *Manager) databaseExists(db string) bool {
+	query := fmt.Sprintf("SELECT 1 FROM pg_database WHERE datname = '%s'", db)
+	out, _ := m.psqlCommand("-tAc", query).Output()
+	return strings.Contains(string(out), "1")
+}
+
+// psqlCommand runs psql against the DB_* connection settings from the config env.
+func (m *Manager) psqlCommand(args ...string) *exec.Cmd {
+	dbUser := valueOr(m.Config.Env["DB_USER"], "postgres")
+	dbPassword := valueOr(m.Config.Env["DB_PASSWORD"], "postgres")
+	dbHost := valueOr(m.Config.Env["DB_HOST"], "localhost")
+	cmd := exec.Command("psql", append([]string{"-U", dbUser, "-h", dbHost}, args...)...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("PGPASSWORD=%s", dbPassword), fmt.Sprintf("PATH=%s", os.Getenv("PATH")))
+	return cmd
 }
 
 func (m *Manager) Doctor() {
@@ -608,6 +776,8 @@ func (m *Manager) Doctor() {
 	fmt.Printf("Resolved poetry: %s\n", resolveTool("poetry", "FLOPPY_POETRY"))
 	fmt.Printf("Resolved bun: %s\n", resolveTool("bun", "FLOPPY_BUN"))
 	fmt.Printf("Resolved python: %s\n", resolveTool("python", "FLOPPY_PYTHON"))
+	fmt.Println()
+	fmt.Printf("Masked secrets: %s\n", valueOr(strings.Join(m.secrets().keys, ", "), "(none)"))
 }
 
 func (m *Manager) Version(version string) {
@@ -637,30 +807,92 @@ func (m *Manager) startService(name string, detached bool, noPTY bool, logCh cha
 		return fmt.Errorf("service '%s' not found", name)
 	}
 
+	// Detached starts have no TUI reading logCh, so hooks print to stdout.
+	hookCh := logCh
+	if detached {
+		hookCh = nil
+	}
+	if err := m.runHook(name, svc, hookPreStart, hookLogger(name, hookPreStart, hookCh)); err != nil {
+		return err
+	}
+	if err := m.launchService(name, svc, detached, noPTY, logCh, statusCh); err != nil {
+		return err
+	}
+	if hookCommand(svc.Hooks, hookPostStart) == "" {
+		return nil
+	}
+	if detached {
+		// The CLI exits right after starting, so run post_start inline.
+		return m.afterStart(name, svc, hookCh, statusCh)
+	}
+	go func() {
+		if err := m.afterStart(name, svc, hookCh, statusCh); err != nil {
+			logCh <- tui.LogLine{Service: "ERROR", Text: fmt.Sprintf("%s: %v", name, err)}
+		}
+	}()
+	return nil
+}
+
+// afterStart runs the post_start hook. When it fails under the abort policy
+// the freshly started service is stopped again and marked as errored.
+func (m *Manager) afterStart(name string, svc config.ServiceDef, logCh chan<- tui.LogLine, statusCh chan<- tui.StatusUpdate) error {
+	err := m.runHook(name, svc, hookPostStart, hookLogger(name, hookPostStart, logCh))
+	if err == nil {
+		return nil
+	}
+	if kerr := m.killTracked(name); kerr != nil {
+		err = fmt.Errorf("%w; %v", err, kerr)
+	}
+	statusCh <- tui.StatusUpdate{Name: name, Status: "error"}
+	m.notifier.crash(name, err.Error())
+	return err
+}
+
+// launchService builds and starts the service process.
+func (m *Manager) launchService(name string, svc config.ServiceDef, detached bool, noPTY bool, logCh chan<- tui.LogLine, statusCh chan<- tui.StatusUpdate) (err error) {
 	cmd, err := m.buildCommand(name, svc)
 	if err != nil {
 		return err
 	}
 	m.prepareCmd(cmd, name, svc)
+	limits, warnings, err := m.applyLimits(name, svc, cmd, detached)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		if detached {
+			fmt.Printf("⚠️  %s\n", w)
+			continue
+		}
+		logCh <- tui.LogLine{Service: "WARN", Text: w}
+	}
+	defer func() {
+		if err != nil {
+			limits.exited() // release the cgroup if the process never started
+		}
+	}()
 
 	if detached {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		out, werr := m.startLogWriter(name)
+		if werr != nil {
+			fmt.Printf("⚠️  %s: output not persisted: %v\n", name, werr)
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+		} else {
+			defer out.Close()
+			cmd.Stdout = out
+			cmd.Stderr = out
+		}
 		if err := cmd.Start(); err != nil {
 			return err
 		}
-		m.trackProcess(name, cmd)
-		m.recordStartedProcess(name, cmd)
-		statusCh <- tui.StatusUpdate{Name: name, Status: "running", PID: cmd.Process.Pid}
-		go func() {
-			_ = cmd.Wait()
-			statusCh <- tui.StatusUpdate{Name: name, Status: "stopped"}
-		}()
+		m.processStarted(name, cmd, limits, statusCh)
+		go m.waitForExit(name, cmd, limits, statusCh)
 		return nil
 	}
 
 	if noPTY {
-		return m.startWithPipes(name, cmd, logCh, statusCh)
+		return m.startWithPipes(name, cmd, limits, logCh, statusCh)
 	}
 
 	ptmx, err := pty.Start(cmd)
@@ -672,13 +904,12 @@ func (m *Manager) startService(name string, detached bool, noPTY bool, logCh cha
 				return ferr
 			}
 			m.prepareCmd(fallback, name, svc)
-			return m.startWithPipes(name, fallback, logCh, statusCh)
+			limits.attach(fallback)
+			return m.startWithPipes(name, fallback, limits, logCh, statusCh)
 		}
 		return err
 	}
-	m.trackProcess(name, cmd)
-	m.recordStartedProcess(name, cmd)
-	statusCh <- tui.StatusUpdate{Name: name, Status: "running", PID: cmd.Process.Pid}
+	m.processStarted(name, cmd, limits, statusCh)
 
 	go func() {
 		defer func() { _ = ptmx.Close() }()
@@ -686,7 +917,7 @@ func (m *Manager) startService(name string, detached bool, noPTY bool, logCh cha
 		for {
 			line, err := reader.ReadString('\n')
 			if line != "" {
-				logCh <- tui.LogLine{Service: name, Text: strings.TrimRight(line, "\r\n")}
+				m.emitLine(name, line, logCh)
 			}
 			if err != nil {
 				break
@@ -694,24 +925,79 @@ func (m *Manager) startService(name string, detached bool, noPTY bool, logCh cha
 		}
 	}()
 
-	go func() {
-		_ = cmd.Wait()
-		statusCh <- tui.StatusUpdate{Name: name, Status: "stopped"}
-	}()
+	go m.waitForExit(name, cmd, limits, statusCh)
 
 	return nil
 }
 
+// processStarted records a freshly started service process and finishes
+// applying its resource limits.
+func (m *Manager) processStarted(name string, cmd *exec.Cmd, limits *limitHandle, statusCh chan<- tui.StatusUpdate) {
+	m.trackProcess(name, cmd)
+	m.recordStartedProcess(name, cmd)
+	limits.started(cmd.Process.Pid)
+	statusCh <- tui.StatusUpdate{Name: name, Status: "running", PID: cmd.Process.Pid}
+}
+
+// waitForExit waits for the service process and reports how it ended:
+// "oom" when it was killed for exceeding its memory limit, else "stopped".
+func (m *Manager) waitForExit(name string, cmd *exec.Cmd, limits *limitHandle, statusCh chan<- tui.StatusUpdate) {
+	m.procMu.Lock()
+	exited := m.exited[name]
+	m.procMu.Unlock()
+	_ = cmd.Wait()
+	oom := limits.exited()
+	code := cmd.ProcessState.ExitCode()
+	m.recordExit(name, cmd.Process.Pid, code, oom)
+	m.procMu.Lock()
+	stopping := m.stopping[name]
+	delete(m.stopping, name)
+	m.procMu.Unlock()
+	status := "stopped"
+	if oom {
+		status = "oom"
+	}
+	statusCh <- tui.StatusUpdate{Name: name, Status: status}
+	switch {
+	case oom:
+		m.notifier.crash(name, "killed for exceeding its memory limit")
+	case !stopping && !requestedStop(cmd.ProcessState):
+		m.notifier.crash(name, fmt.Sprintf("exited unexpectedly (%s)", cmd.ProcessState))
+	}
+	if exited != nil {
+		close(exited)
+	}
+}
+
 func (m *Manager) prepareCmd(cmd *exec.Cmd, name string, svc config.ServiceDef) {
 	cmd.Dir = servicePath(m.Root, name, svc.Path)
-	cmd.Env = append(os.Environ(), config.MergeEnv(m.Config.Env, svc.Env)...)
+	cmd.Env = m.serviceEnv(name, svc)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// serviceEnv is the environment a service process is started with: the OS
+// environment followed by serviceVars.
+func (m *Manager) serviceEnv(name string, svc config.ServiceDef) []string {
+	return append(os.Environ(), m.serviceVars(name, svc)...)
+}
+
+// serviceVars are the variables floppy sets for a service: PGAPPNAME, the
+// merged config env and the injected PORT. PGAPPNAME makes libpq clients
+// report the service name as application_name, which the TUI uses to tell
+// whose Postgres sessions are whose. It is left out when the config env
+// sets its own.
+func (m *Manager) serviceVars(name string, svc config.ServiceDef) []string {
+	vars := config.MergeEnv(m.Config.Env, svc.Env)
+	if _, set := envMap(vars)["PGAPPNAME"]; !set {
+		vars = append([]string{"PGAPPNAME=" + name}, vars...)
+	}
 	if svc.Port > 0 {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("PORT=%d", svc.Port))
+		vars = append(vars, fmt.Sprintf("PORT=%d", svc.Port))
 	}
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return vars
 }
 
-func (m *Manager) startWithPipes(name string, cmd *exec.Cmd, logCh chan<- tui.LogLine, statusCh chan<- tui.StatusUpdate) error {
+func (m *Manager) startWithPipes(name string, cmd *exec.Cmd, limits *limitHandle, logCh chan<- tui.LogLine, statusCh chan<- tui.StatusUpdate) error {
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 	stdout, err := cmd.StdoutPipe()
@@ -725,27 +1011,22 @@ func (m *Manager) startWithPipes(name string, cmd *exec.Cmd, logCh chan<- tui.Lo
 	if err := cmd.Start(); err != nil {
 		return err
 	}
-	m.trackProcess(name, cmd)
-	m.recordStartedProcess(name, cmd)
-	statusCh <- tui.StatusUpdate{Name: name, Status: "running", PID: cmd.Process.Pid}
+	m.processStarted(name, cmd, limits, statusCh)
 
-	go readLines(name, stdout, logCh)
-	go readLines(name, stderr, logCh)
+	go m.readLines(name, stdout, logCh)
+	go m.readLines(name, stderr, logCh)
 
-	go func() {
-		_ = cmd.Wait()
-		statusCh <- tui.StatusUpdate{Name: name, Status: "stopped"}
-	}()
+	go m.waitForExit(name, cmd, limits, statusCh)
 
 	return nil
 }
 
-func readLines(service string, r io.Reader, logCh chan<- tui.LogLine) {
+func (m *Manager) readLines(service string, r io.Reader, logCh chan<- tui.LogLine) {
 	reader := bufio.NewReader(r)
 	for {
 		line, err := reader.ReadString('\n')
 		if line != "" {
-			logCh <- tui.LogLine{Service: service, Text: strings.TrimRight(line, "\r\n")}
+			m.emitLine(service, line, logCh)
 		}
 		if err != nil {
 			return
@@ -788,12 +1069,35 @@ func (m *Manager) trackProcess(name string, cmd *exec.Cmd) {
 	m.procMu.Lock()
 	defer m.procMu.Unlock()
 	m.processes[name] = cmd
+	m.exited[name] = make(chan struct{})
+	delete(m.stopping, name) // a stop requested for the previous process
+}
+
+func (m *Manager) hasWatchedService(services []string) bool {
+	for _, name := range services {
+		if m.Config.Services[name].Watch != nil {
+			return true
+		}
+	}
+	return false
 }
 
 func (m *Manager) recordStartedProcess(name string, cmd *exec.Cmd) {
 	if cmd == nil || cmd.Process == nil {
 		return
 	}
+	// Restarts counts this manager's starts only: an entry left by an
+	// earlier `floppy up` belongs to another session.
+	m.procMu.Lock()
+	if m.starts == nil {
+		m.starts = map[string]int{}
+	}
+	m.starts[name]++
+	restarts := m.starts[name] - 1
+	m.procMu.Unlock()
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
 	state := loadProcessState()
 	pgid, _ := syscall.Getpgid(cmd.Process.Pid)
 	state.Entries[name] = ProcessEntry{
@@ -803,12 +1107,29 @@ func (m *Manager) recordStartedProcess(name string, cmd *exec.Cmd) {
 		Cwd:       cmd.Dir,
 		Cmdline:   strings.TrimSpace(strings.Join(cmd.Args, " ")),
 		StartTime: processStartTime(cmd.Process.Pid),
+		Restarts:  restarts,
 	}
 	if err := saveProcessState(state); err != nil {
 		fmt.Printf("Warning: failed to persist process state for %s: %v\n", name, err)
 	}
 }
 
+// recordExit stores how a tracked process ended so `ps` can report it after
+// the fact. Entries that were already removed or replaced are left alone.
+func (m *Manager) recordExit(name string, pid int, exitCode int, oom bool) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	state := loadProcessState()
+	entry, ok := state.Entries[name]
+	if !ok || entry.PID != pid {
+		return
+	}
+	entry.ExitCode = &exitCode
+	entry.OOMKilled = oom
+	state.Entries[name] = entry
+	_ = saveProcessState(state)
+}
+
 func (m *Manager) snapshotStatuses() []tui.ServiceRow {
 	rows := make([]tui.ServiceRow, 0, len(m.statuses))
 	keys := make([]string, 0, len(m.statuses))
@@ -818,11 +1139,37 @@ func (m *Manager) snapshotStatuses() []tui.ServiceRow {
 	sort.Strings(keys)
 	for _, name := range keys {
 		status := m.statuses[name]
-		rows = append(rows, tui.ServiceRow{Name: status.Name, Status: status.Status, Port: status.Port})
+		row := tui.ServiceRow{Name: status.Name, Status: status.Status, Port: status.Port, Task: status.Type == "task"}
+		if lim, err := parseLimits(m.Config.Services[name].Limits); err == nil {
+			row.MemoryLimit = lim.MemoryBytes
+		}
+		rows = append(rows, row)
 	}
 	return rows
 }
 
+// checkTUIConfig rejects unknown themes, actions and colors in the tui
+// section before any service starts.
+func (m *Manager) checkTUIConfig() error {
+	if m.Config.TUI == nil {
+		return nil
+	}
+	if _, err := tui.ThemeByName(m.Config.TUI.Theme); err != nil {
+		return fmt.Errorf("tui.theme: %w", err)
+	}
+	if _, err := tui.ParseKeys(m.Config.TUI.Keys); err != nil {
+		return fmt.Errorf("tui.keys: %w", err)
+	}
+	for name, color := range m.Config.TUI.Colors {
+		if !tuiColorRe.MatchString(color) {
+			return fmt.Errorf("tui.colors.%s: %q is not an ANSI color number or #rrggbb", name, color)
+		}
+	}
+	return nil
+}
+
+var tuiColorRe = regexp.MustCompile(`^([0-9]|[1-9][0-9]|1[0-9][0-9]|2[0-4][0-9]|25[0-5]|#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6})$`)
+
 func (m *Manager) validatePorts(services []string, force bool) error {
 	ports := map[int][]string{}
 	for _, name := range services {
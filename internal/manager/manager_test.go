@@ -1,7 +1,9 @@
 package manager
 
 import (
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"floppy-go/internal/config"
@@ -135,3 +137,84 @@ func TestNew(t *testing.T) {
 		t.Errorf("New: Root = %q, want /path/to", m.Root)
 	}
 }
+
+func Test_migrationDirs(t *testing.T) {
+	no := false
+	m := New(&config.Config{Services: map[string]config.ServiceDef{
+		"orcha":        {Type: "api", Migrate: &no},
+		"orcha-worker": {Type: "worker", Path: "orcha"},
+		"eventa":       {Type: "api"},
+		"eventa-nats":  {Type: "worker", Path: "eventa"},
+		"portal":       {Type: "portal"},
+	}}, "/srv/services.yaml")
+	got := m.migrationDirs()
+	if len(got) != 1 || got[0] != "/srv/eventa" {
+		t.Errorf("migrationDirs = %v, want [/srv/eventa]", got)
+	}
+}
+
+func Test_parseLimits(t *testing.T) {
+	lim, err := parseLimits(&config.LimitsConfig{Memory: "1g", CPU: 1.5, NoFile: 4096})
+	if err != nil {
+		t.Fatalf("parseLimits: %v", err)
+	}
+	if lim.MemoryBytes != 1<<30 || lim.CPU != 1.5 || lim.NoFile != 4096 {
+		t.Errorf("parseLimits = %+v", lim)
+	}
+	if lim, err := parseLimits(nil); err != nil || !lim.empty() {
+		t.Errorf("parseLimits(nil) = %+v, %v", lim, err)
+	}
+	if _, err := parseLimits(&config.LimitsConfig{Memory: "lots"}); err == nil {
+		t.Error("parseLimits(bad memory): want error")
+	}
+}
+
+func Test_withNoFile_appliesToChildren(t *testing.T) {
+	// The limit must already be set when the command forks, so a grandchild
+	// sees it too.
+	cmd := exec.Command("sh", "-c", `sh -c "ulimit -n"`)
+	withNoFile(cmd, 256)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "256" {
+		t.Errorf("child nofile = %q, want 256", got)
+	}
+}
+
+func Test_applyLimits_detachedMemoryWarning(t *testing.T) {
+	m := New(&config.Config{}, "/tmp/services.yaml")
+	svc := config.ServiceDef{Limits: &config.LimitsConfig{Memory: "1g"}}
+	h, warnings, err := m.applyLimits("api", svc, exec.Command("true"), true)
+	if err != nil {
+		t.Fatalf("applyLimits: %v", err)
+	}
+	defer h.exited()
+	if h.cgroup != nil {
+		t.Skip("cgroup delegation available; no warning expected")
+	}
+	if h.pollMemory || len(warnings) != 1 || !strings.Contains(warnings[0], "not enforced") {
+		t.Errorf("detached without cgroup: pollMemory=%v warnings=%q", h.pollMemory, warnings)
+	}
+}
+
+func Test_watchIgnored(t *testing.T) {
+	patterns := []string{"*.pyc", "__pycache__", "migrations/versions", "dist/"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/svc/app/main.py", false},
+		{"/svc/app/main.pyc", true},
+		{"/svc/app/__pycache__/x.py", true},
+		{"/svc/migrations/versions/001.py", true},
+		{"/svc/migrations/env.py", false},
+		{"/svc/dist", true},
+	}
+	for _, tt := range tests {
+		if got := watchIgnored("/svc", tt.path, patterns); got != tt.want {
+			t.Errorf("watchIgnored(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
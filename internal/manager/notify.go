@@ -0,0 +1,183 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"floppy-go/internal/config"
+	"floppy-go/internal/tui"
+)
+
+// notifyInterval is the minimum time between two notifications for the same
+// service and event, so a traceback or a crash loop alerts once.
+const notifyInterval = 10 * time.Second
+
+var ansiCSI = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+
+const (
+	notifyCrash   = "crash"
+	notifyPattern = "pattern"
+)
+
+// notifier alerts through the terminal and an optional command when
+// services crash or log matching lines. A nil notifier does nothing.
+type notifier struct {
+	bell     bool
+	desktop  string
+	command  string
+	patterns []*regexp.Regexp
+	out      io.Writer
+	logCh    chan<- tui.LogLine
+
+	mu   sync.Mutex
+	last map[string]time.Time
+	off  atomic.Bool
+	now  func() time.Time
+}
+
+// newNotifier returns nil when cfg is nil.
+func newNotifier(cfg *config.NotifyConfig, out io.Writer, logCh chan<- tui.LogLine) (*notifier, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	switch cfg.Desktop {
+	case "", "osc9", "osc777":
+	default:
+		return nil, fmt.Errorf("notify.desktop: unknown %q (use osc9 or osc777)", cfg.Desktop)
+	}
+	n := &notifier{
+		bell:    cfg.Bell,
+		desktop: cfg.Desktop,
+		command: strings.TrimSpace(cfg.Command),
+		out:     out,
+		logCh:   logCh,
+		last:    map[string]time.Time{},
+		now:     time.Now,
+	}
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("notify.patterns: %w", err)
+		}
+		n.patterns = append(n.patterns, re)
+	}
+	return n, nil
+}
+
+// crash reports a service that stopped or failed without being asked to.
+func (n *notifier) crash(service, message string) {
+	n.send(service, notifyCrash, message)
+}
+
+// line checks a log line, without colors, against the configured patterns.
+func (n *notifier) line(service, text string) {
+	if n == nil || len(n.patterns) == 0 {
+		return
+	}
+	text = ansiCSI.ReplaceAllString(text, "")
+	for _, re := range n.patterns {
+		if re.MatchString(text) {
+			n.send(service, notifyPattern, strings.TrimSpace(text))
+			return
+		}
+	}
+}
+
+// close stops further notifications, e.g. while floppy shuts services down.
+func (n *notifier) close() {
+	if n != nil {
+		n.off.Store(true)
+	}
+}
+
+func (n *notifier) send(service, event, message string) {
+	if n == nil || n.off.Load() {
+		return
+	}
+	n.mu.Lock()
+	key := service + "\x00" + event
+	now := n.now()
+	if last, ok := n.last[key]; ok && now.Sub(last) < notifyInterval {
+		n.mu.Unlock()
+		return
+	}
+	n.last[key] = now
+	n.mu.Unlock()
+
+	message = terminalSafe(message)
+	if r := []rune(message); len(r) > 200 {
+		message = string(r[:200]) + "…"
+	}
+	title := "floppy: " + service
+	var b strings.Builder
+	if n.bell {
+		b.WriteString("\a")
+	}
+	switch n.desktop {
+	case "osc9":
+		b.WriteString("\x1b]9;" + title + ": " + message + "\a")
+	case "osc777":
+		b.WriteString("\x1b]777;notify;" + title + ";" + message + "\a")
+	}
+	if b.Len() > 0 && n.out != nil {
+		_, _ = io.WriteString(n.out, b.String())
+	}
+	if n.command != "" {
+		go n.runCommand(service, event, message)
+	}
+}
+
+func (n *notifier) runCommand(service, event, message string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", n.command)
+	cmd.Env = append(os.Environ(),
+		"FLOPPY_SERVICE="+service,
+		"FLOPPY_EVENT="+event,
+		"FLOPPY_MESSAGE="+message,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil && n.logCh != nil {
+		text := fmt.Sprintf("notify command failed: %v", err)
+		if s := strings.TrimSpace(string(out)); s != "" {
+			text += ": " + s
+		}
+		n.logCh <- tui.LogLine{Service: "WARN", Text: text}
+	}
+}
+
+// terminalSafe drops control characters so a message cannot end the escape
+// sequence it is sent in.
+func terminalSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// requestedStop reports whether the process ended on a stop signal, as
+// `floppy stop` from another shell sends.
+func requestedStop(state *os.ProcessState) bool {
+	if state == nil {
+		return false
+	}
+	ws, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return false
+	}
+	switch ws.Signal() {
+	case syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package manager
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"floppy-go/internal/config"
+)
+
+func Test_notifier_send(t *testing.T) {
+	var out bytes.Buffer
+	n, err := newNotifier(&config.NotifyConfig{Bell: true, Desktop: "osc9", Patterns: []string{`Traceback`, `FATAL`}}, &out, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 10, 6, 9, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return now }
+
+	n.crash("api", "exited unexpectedly (exit status 1)")
+	if got, want := out.String(), "\a\x1b]9;floppy: api: exited unexpectedly (exit status 1)\a"; got != want {
+		t.Errorf("crash wrote %q, want %q", got, want)
+	}
+
+	out.Reset()
+	n.crash("api", "again")
+	if out.Len() != 0 {
+		t.Errorf("second crash within %v should be dropped, wrote %q", notifyInterval, out.String())
+	}
+	n.line("api", "\x1b[31mTraceback (most recent call last):\x1b[0m")
+	if !strings.Contains(out.String(), "api: Traceback (most recent call last):\a") {
+		t.Errorf("pattern match should notify without colors, wrote %q", out.String())
+	}
+
+	out.Reset()
+	n.line("worker", "all good")
+	now = now.Add(notifyInterval)
+	n.crash("api", "bye\x07\x1b]0;evil")
+	if got := out.String(); got != "\a\x1b]9;floppy: api: bye]0;evil\a" {
+		t.Errorf("got %q", got)
+	}
+
+	out.Reset()
+	n.close()
+	n.crash("worker", "after close")
+	if out.Len() != 0 {
+		t.Error("closed notifier should stay quiet")
+	}
+}
+
+func Test_newNotifier(t *testing.T) {
+	if n, err := newNotifier(nil, nil, nil); n != nil || err != nil {
+		t.Errorf("nil config: %v, %v", n, err)
+	}
+	if _, err := newNotifier(&config.NotifyConfig{Desktop: "growl"}, nil, nil); err == nil {
+		t.Error("unknown desktop: want error")
+	}
+	if _, err := newNotifier(&config.NotifyConfig{Patterns: []string{"("}}, nil, nil); err == nil {
+		t.Error("bad pattern: want error")
+	}
+	var nilNotifier *notifier
+	nilNotifier.crash("api", "ignored")
+	nilNotifier.line("api", "ignored")
+}
+
+func Test_notifier_command(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "out")
+	var out bytes.Buffer
+	n, err := newNotifier(&config.NotifyConfig{Desktop: "osc777", Command: `printf '%s|%s|%s' "$FLOPPY_SERVICE" "$FLOPPY_EVENT" "$FLOPPY_MESSAGE" > ` + file}, &out, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.crash("api", "exited")
+	if got := out.String(); got != "\x1b]777;notify;floppy: api;exited\a" {
+		t.Errorf("osc777 wrote %q", got)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(file)
+		if string(data) == "api|crash|exited" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("command output = %q", data)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func Test_requestedStop(t *testing.T) {
+	term := exec.Command("sh", "-c", "kill -TERM $$")
+	_ = term.Run()
+	if !requestedStop(term.ProcessState) {
+		t.Errorf("SIGTERM should count as a requested stop: %v", term.ProcessState)
+	}
+	fail := exec.Command("sh", "-c", "exit 3")
+	_ = fail.Run()
+	if requestedStop(fail.ProcessState) {
+		t.Error("exit 3 is a crash")
+	}
+	segv := exec.Command("sh", "-c", "kill -SEGV $$")
+	_ = segv.Run()
+	if requestedStop(segv.ProcessState) {
+		t.Error("SIGSEGV is a crash")
+	}
+}
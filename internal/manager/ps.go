@@ -0,0 +1,225 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"floppy-go/internal/procstats"
+)
+
+// PsOptions controls how `floppy ps` renders.
+type PsOptions struct {
+	Quiet  bool
+	Format string // "table" (default) or "json"
+	Watch  bool
+}
+
+// psWatchInterval is how often `ps --watch` refreshes.
+const psWatchInterval = 2 * time.Second
+
+// psSampleDelay is the gap between the two CPU readings of a one-shot ps.
+const psSampleDelay = 250 * time.Millisecond
+
+// ServiceProcess is one row of `floppy ps`, merged from tracked process state
+// and port detection.
+type ServiceProcess struct {
+	Service   string  `json:"service"`
+	Type      string  `json:"type"`
+	Status    string  `json:"status"` // running, exited, oom-killed
+	Tracked   bool    `json:"tracked"`
+	PID       int     `json:"pid"`
+	PGID      int     `json:"pgid"`
+	Port      int     `json:"port,omitempty"`
+	Health    string  `json:"health"` // healthy, unhealthy, unknown
+	StartedAt string  `json:"started_at,omitempty"`
+	UptimeSec int64   `json:"uptime_seconds"`
+	CPUPct    float64 `json:"cpu_percent"`
+	RSSBytes  int64   `json:"rss_bytes"`
+	Processes int     `json:"processes"`
+	Restarts  int     `json:"restarts"`
+	ExitCode  *int    `json:"exit_code,omitempty"`
+}
+
+func (m *Manager) Ps(opts PsOptions) error {
+	switch opts.Format {
+	case "", "table", "json":
+	default:
+		return fmt.Errorf("unknown format %q (use table or json)", opts.Format)
+	}
+
+	sampler := procstats.NewSampler()
+	if !opts.Watch {
+		rows := m.collectPs(sampler, true)
+		return m.printPs(rows, opts)
+	}
+
+	first := true
+	for {
+		rows := m.collectPs(sampler, first)
+		first = false
+		if opts.Format != "json" {
+			// Clear screen and home cursor between refreshes.
+			fmt.Print("\x1b[H\x1b[2J")
+			fmt.Printf("Every %s: floppy ps    %s\n\n", psWatchInterval, time.Now().Format("15:04:05"))
+		}
+		if err := m.printPs(rows, opts); err != nil {
+			return err
+		}
+		time.Sleep(psWatchInterval)
+	}
+}
+
+// collectPs merges tracked entries with services detected on their ports.
+// When prime is true the CPU sampler is read twice so CPU % is meaningful on
+// the first call.
+func (m *Manager) collectPs(sampler *procstats.Sampler, prime bool) []ServiceProcess {
+	state := loadProcessState()
+	detected := DetectRunningServices(m.Config, m.Root)
+
+	rows := map[string]*ServiceProcess{}
+	for name, entry := range state.Entries {
+		svc := m.Config.Services[name]
+		row := &ServiceProcess{
+			Service:   name,
+			Type:      svc.Type,
+			Tracked:   true,
+			PID:       entry.PID,
+			PGID:      entry.PGID,
+			Port:      svc.Port,
+			StartedAt: entry.StartTime,
+			Restarts:  entry.Restarts,
+			Status:    "exited",
+			Health:    "unknown",
+		}
+		if processAlive(entry.PID) {
+			row.Status = "running"
+		} else if entry.OOMKilled {
+			row.Status = "oom-killed"
+		}
+		if entry.ExitCode != nil && row.Status != "running" {
+			row.ExitCode = entry.ExitCode
+		}
+		rows[name] = row
+	}
+	for name, info := range detected {
+		if _, ok := rows[name]; ok {
+			continue
+		}
+		pgid, _ := syscall.Getpgid(info.PID)
+		rows[name] = &ServiceProcess{
+			Service:   name,
+			Type:      info.Type,
+			Status:    "running",
+			PID:       info.PID,
+			PGID:      pgid,
+			Port:      info.Port,
+			StartedAt: processStartTime(info.PID),
+			Health:    "unknown",
+		}
+	}
+
+	if prime {
+		for _, row := range rows {
+			if row.Status == "running" {
+				_, _ = sampler.Sample(groupOf(row))
+			}
+		}
+		time.Sleep(psSampleDelay)
+	}
+
+	out := make([]ServiceProcess, 0, len(rows))
+	for name, row := range rows {
+		if row.Status == "running" {
+			if started, ok := parseStartTime(row.StartedAt); ok {
+				row.UptimeSec = int64(time.Since(started).Seconds())
+			}
+			if usage, err := sampler.Sample(groupOf(row)); err == nil {
+				row.CPUPct = usage.CPUPct
+				row.RSSBytes = usage.RSSBytes
+				row.Processes = usage.Processes
+			}
+			row.Health = portHealth(row, detected[name])
+		}
+		out = append(out, *row)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Service < out[j].Service })
+	return out
+}
+
+// groupOf returns the process group to measure, falling back to the PID.
+func groupOf(row *ServiceProcess) int {
+	if row.PGID > 0 {
+		return row.PGID
+	}
+	return row.PID
+}
+
+// portHealth reports whether the service's port is held by its own process
+// group. Services without a port are reported as unknown.
+func portHealth(row *ServiceProcess, info RunningService) string {
+	if row.Port <= 0 {
+		return "unknown"
+	}
+	if info.PID <= 0 {
+		return "unhealthy"
+	}
+	if info.PID == row.PID {
+		return "healthy"
+	}
+	if pgid, err := syscall.Getpgid(info.PID); err == nil && pgid == groupOf(row) {
+		return "healthy"
+	}
+	return "unhealthy"
+}
+
+func (m *Manager) printPs(rows []ServiceProcess, opts PsOptions) error {
+	if opts.Format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		if !opts.Watch {
+			enc.SetIndent("", "  ")
+		}
+		return enc.Encode(rows)
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("No services running")
+		return nil
+	}
+
+	if opts.Quiet {
+		for _, row := range rows {
+			if row.Status == "running" {
+				fmt.Println(row.Service)
+			}
+		}
+		return nil
+	}
+
+	fmt.Printf("%-24s %-10s %-9s %-7s %-6s %-9s %6s %7s %-9s %s\n",
+		"SERVICE", "STATUS", "SOURCE", "PID", "PORT", "UPTIME", "CPU%", "RSS", "HEALTH", "RESTARTS")
+	fmt.Println(strings.Repeat("-", 102))
+	for _, row := range rows {
+		source := "untracked"
+		if row.Tracked {
+			source = "tracked"
+		}
+		port := "-"
+		if row.Port > 0 {
+			port = fmt.Sprintf("%d", row.Port)
+		}
+		uptime, cpu, rss := "-", "-", "-"
+		if row.Status == "running" {
+			uptime = procstats.FormatUptime(time.Duration(row.UptimeSec) * time.Second)
+			cpu = fmt.Sprintf("%.1f", row.CPUPct)
+			rss = procstats.FormatBytes(row.RSSBytes)
+		}
+		fmt.Printf("%-24s %-10s %-9s %-7d %-6s %-9s %6s %7s %-9s %d\n",
+			row.Service, row.Status, source, row.PID, port, uptime, cpu, rss, row.Health, row.Restarts)
+	}
+	return nil
+}
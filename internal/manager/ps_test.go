@@ -0,0 +1,29 @@
+package manager
+
+import "testing"
+
+func Test_parseStartTime(t *testing.T) {
+	got, ok := parseStartTime("Mon Oct  6 09:15:02 2025")
+	if !ok {
+		t.Fatal("parseStartTime: want ok")
+	}
+	if got.Day() != 6 || got.Hour() != 9 || got.Second() != 2 {
+		t.Errorf("parseStartTime = %v", got)
+	}
+	if _, ok := parseStartTime(""); ok {
+		t.Error("parseStartTime(empty): want !ok")
+	}
+}
+
+func Test_portHealth(t *testing.T) {
+	row := &ServiceProcess{PID: 10, PGID: 10, Port: 8000}
+	if got := portHealth(row, RunningService{PID: 10}); got != "healthy" {
+		t.Errorf("portHealth(own pid) = %q", got)
+	}
+	if got := portHealth(row, RunningService{}); got != "unhealthy" {
+		t.Errorf("portHealth(no listener) = %q", got)
+	}
+	if got := portHealth(&ServiceProcess{PID: 10}, RunningService{}); got != "unknown" {
+		t.Errorf("portHealth(no port) = %q", got)
+	}
+}
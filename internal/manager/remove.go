@@ -0,0 +1,170 @@
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"floppy-go/internal/tui"
+)
+
+// RemoveOptions controls `stop --remove` / `down --remove`.
+type RemoveOptions struct {
+	Volumes bool      // also drop the service databases created by setup
+	Yes     bool      // skip the confirmation before dropping databases
+	In      io.Reader // confirmation input; defaults to stdin
+}
+
+// Remove forgets stopped services: their process-state entries and build
+// records, persisted logs, TUI scrollback spill files and per-service
+// cgroups. Docker containers are
+// not touched: floppy does not create them, so a container named after a
+// service is the user's own. Services that are still running are left
+// alone. With no names every configured or tracked service is removed.
+func (m *Manager) Remove(services []string, opts RemoveOptions) error {
+	stateMu.Lock()
+	state := loadProcessState()
+	if len(services) == 0 {
+		seen := map[string]bool{}
+		for _, name := range append(stableKeys(m.Config.Services), stableKeys(state.Entries)...) {
+			if !seen[name] {
+				seen[name] = true
+				services = append(services, name)
+			}
+		}
+	}
+
+	removed := []string{}
+	for _, name := range services {
+		if entry, ok := state.Entries[name]; ok && processAlive(entry.PID) {
+			fmt.Printf("Skipping removal of %s: still running (PID %d)\n", name, entry.PID)
+			continue
+		}
+		what := []string{}
+		if _, ok := state.Entries[name]; ok {
+			delete(state.Entries, name)
+			what = append(what, "state")
+		}
+		if _, ok := state.Builds[name]; ok {
+			delete(state.Builds, name)
+			what = append(what, "build record")
+		}
+		what = append(what, m.removeResources(name)...)
+		if len(what) > 0 {
+			fmt.Printf("🧹 Removed %s: %s\n", name, strings.Join(what, ", "))
+		}
+		removed = append(removed, name)
+	}
+	err := saveProcessState(state)
+	stateMu.Unlock()
+	if err != nil {
+		fmt.Printf("Warning: failed to persist process state: %v\n", err)
+	}
+
+	if opts.Volumes {
+		return m.dropDatabases(removed, opts)
+	}
+	return nil
+}
+
+// spillDir is where the TUI spills scrollback, one scrollback-* directory
+// per session.
+func spillDir() string {
+	return filepath.Join(floppyCacheDir(), "scrollback")
+}
+
+// removeResources deletes what floppy created outside the state file for a
+// service and returns a description of each thing removed.
+func (m *Manager) removeResources(name string) []string {
+	what := []string{}
+	if dir := serviceLogDir(name); dirExists(dir) {
+		if err := os.RemoveAll(dir); err != nil {
+			fmt.Printf("Warning: %s: failed to remove logs: %v\n", name, err)
+		} else {
+			what = append(what, "logs")
+		}
+	}
+	spills, _ := filepath.Glob(filepath.Join(spillDir(), "scrollback-*", tui.SpillFileName(name)))
+	removedSpill := false
+	for _, f := range spills {
+		if err := os.Remove(f); err != nil {
+			fmt.Printf("Warning: %s: failed to remove scrollback %s: %v\n", name, f, err)
+		} else {
+			removedSpill = true
+		}
+	}
+	if removedSpill {
+		what = append(what, "scrollback")
+	}
+	if path := cgroupPath(name); path != "" && dirExists(path) {
+		if err := os.Remove(path); err != nil {
+			fmt.Printf("Warning: %s: failed to remove cgroup %s: %v\n", name, path, err)
+		} else {
+			what = append(what, "cgroup")
+		}
+	}
+	return what
+}
+
+// dropDatabases drops the databases createDatabases made for services, after
+// confirmation.
+func (m *Manager) dropDatabases(services []string, opts RemoveOptions) error {
+	dbs := []string{}
+	for _, name := range services {
+		if _, ok := m.Config.Services[name]; !ok {
+			continue
+		}
+		for _, db := range serviceDatabases(name) {
+			if m.databaseExists(db) {
+				dbs = append(dbs, db)
+			}
+		}
+	}
+	if len(dbs) == 0 {
+		fmt.Println("No service databases to drop")
+		return nil
+	}
+
+	if !opts.Yes {
+		in := opts.In
+		if in == nil {
+			in = os.Stdin
+		}
+		prompt := fmt.Sprintf("Drop %d database(s): %s? This cannot be undone [y/N] ", len(dbs), strings.Join(dbs, ", "))
+		if !confirm(in, os.Stdout, prompt) {
+			fmt.Println("Databases kept")
+			return nil
+		}
+	}
+
+	for _, db := range dbs {
+		drop := m.psqlCommand("-c", fmt.Sprintf("DROP DATABASE IF EXISTS \"%s\";", db))
+		drop.Stdout = io.Discard
+		drop.Stderr = os.Stderr
+		if err := drop.Run(); err != nil {
+			fmt.Printf("Failed to drop database %s: %v\n", db, err)
+			continue
+		}
+		fmt.Printf("Dropped database: %s\n", db)
+	}
+	return nil
+}
+
+// confirm asks a yes/no question; anything but y/yes is no.
+func confirm(in io.Reader, out io.Writer, prompt string) bool {
+	fmt.Fprint(out, prompt)
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"floppy-go/internal/config"
+)
+
+func Test_confirm(t *testing.T) {
+	for in, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "": false} {
+		var out bytes.Buffer
+		if got := confirm(strings.NewReader(in), &out, "sure? "); got != want {
+			t.Errorf("confirm(%q) = %v, want %v", in, got, want)
+		}
+		if out.String() != "sure? " {
+			t.Errorf("prompt = %q", out.String())
+		}
+	}
+}
+
+func Test_Remove(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("FLOPPY_STATE_FILE", filepath.Join(tmp, "state.json"))
+	t.Setenv("XDG_CACHE_HOME", tmp)
+	t.Setenv("FLOPPY_CGROUP_ROOT", filepath.Join(tmp, "no-cgroup"))
+
+	if err := saveProcessState(ProcessState{
+		Entries: map[string]ProcessEntry{
+			"api":    {Service: "api", PID: 0},
+			"worker": {Service: "worker", PID: os.Getpid()},
+		},
+		Builds: map[string]BuildRecord{"api": {InputsHash: "abc"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	logs := serviceLogDir("api")
+	if err := os.MkdirAll(logs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	session := filepath.Join(spillDir(), "scrollback-1")
+	if err := os.MkdirAll(session, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"api.jsonl", "worker.jsonl"} {
+		if err := os.WriteFile(filepath.Join(session, name), []byte("{}\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := New(&config.Config{Services: map[string]config.ServiceDef{"api": {}, "worker": {}}}, "")
+	if err := m.Remove([]string{"api", "worker"}, RemoveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	state := loadProcessState()
+	if _, ok := state.Entries["api"]; ok {
+		t.Error("api state entry should be removed")
+	}
+	if _, ok := state.Builds["api"]; ok {
+		t.Error("api build record should be removed")
+	}
+	if _, ok := state.Entries["worker"]; !ok {
+		t.Error("running worker should be kept")
+	}
+	if dirExists(logs) {
+		t.Error("api logs should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(session, "api.jsonl")); !os.IsNotExist(err) {
+		t.Error("api scrollback spill should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(session, "worker.jsonl")); err != nil {
+		t.Error("running worker's scrollback spill should be kept")
+	}
+}
@@ -0,0 +1,67 @@
+package manager
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"floppy-go/internal/config"
+	"floppy-go/internal/tui"
+)
+
+func Test_secretMasker_redact(t *testing.T) {
+	s := newSecretMasker([]string{"MASTER_*", "SALT", "*_auth"},
+		[]string{"DB_USER=postgres", "DB_PASSWORD=postgres", "MASTER_SIGNING=s3cr3t-value", "SALT=abc", "STRIPE_KEY=sk_live_123456", "BASIC_AUTH=admin:letmein"},
+		[]string{"SESSION_SECRET=s3cr3t", "PORT=8000"},
+	)
+	cases := map[string]string{
+		"boot with key s3cr3t-value":          "boot with key " + secretMask,
+		"session s3cr3t ok":                   "session " + secretMask + " ok",
+		"stripe=sk_live_123456":               "stripe=" + secretMask,
+		"connecting as postgres on 8000":      "connecting as " + secretMask + " on 8000",
+		"salt abc is too short to redact":     "salt abc is too short to redact",
+		"url mysql://app:hunter2@db:3306/app": "url mysql://app:" + secretMask + "@db:3306/app",
+		"auth admin:letmein":                  "auth " + secretMask,
+	}
+	for in, want := range cases {
+		if got := s.redact(in); got != want {
+			t.Errorf("redact(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := strings.Join(s.keys, ","); got != "BASIC_AUTH,DB_PASSWORD,MASTER_SIGNING,SALT,SESSION_SECRET,STRIPE_KEY" {
+		t.Errorf("keys = %s", got)
+	}
+	if got := s.maskVar("SALT=abc"); got != "SALT="+secretMask {
+		t.Errorf("maskVar(SALT) = %q", got)
+	}
+	if got := s.maskVar("PORT=8000"); got != "PORT=8000" {
+		t.Errorf("maskVar(PORT) = %q", got)
+	}
+	for _, key := range []string{"PWD", "OLDPWD", "AUTH_ENABLED", "AUTHOR_NAME", "DATABASE_DSN", "KEYBOARD"} {
+		if isSecretKey(key) {
+			t.Errorf("isSecretKey(%s) = true", key)
+		}
+	}
+}
+
+func Test_emitLine_redacts(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer closeServiceLogs()
+	m := New(&config.Config{
+		Env:      map[string]any{"MASTER_SECRET_KEY": "abcdef123456"},
+		Services: map[string]config.ServiceDef{"api": {Type: "api"}},
+	}, filepath.Join(t.TempDir(), "services.yaml"))
+	logCh := make(chan tui.LogLine, 1)
+	m.emitLine("api", "config: MASTER_SECRET_KEY=abcdef123456\n", logCh)
+	if line := <-logCh; line.Text != "config: MASTER_SECRET_KEY="+secretMask {
+		t.Errorf("TUI line = %q", line.Text)
+	}
+}
+
+func Test_secrets_ignoresOSEnv(t *testing.T) {
+	t.Setenv("SHELL_SECRET_TOKEN", "/home/dev/orcha")
+	m := New(&config.Config{Env: map[string]any{"API_TOKEN": "tok-123456"}}, filepath.Join(t.TempDir(), "services.yaml"))
+	if got := m.secrets().redact(`File "/home/dev/orcha/app.py" tok-123456`); got != `File "/home/dev/orcha/app.py" `+secretMask {
+		t.Errorf("redact = %q", got)
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 )
 
 type ProcessEntry struct {
@@ -18,25 +20,43 @@ type ProcessEntry struct {
 	Cwd       string `json:"cwd"`
 	Cmdline   string `json:"cmdline"`
 	StartTime string `json:"start_time"`
+	Restarts  int    `json:"restarts,omitempty"`
+	ExitCode  *int   `json:"exit_code,omitempty"`
+	OOMKilled bool   `json:"oom_killed,omitempty"`
 }
 
+// stateMu serialises read-modify-write cycles of the state file within one
+// floppy process (e.g. exit bookkeeping racing with Stop).
+var stateMu sync.Mutex
+
 type ProcessState struct {
 	Entries map[string]ProcessEntry `json:"entries"`
+	Builds  map[string]BuildRecord  `json:"builds,omitempty"`
 }
 
 func stateFilePath() string {
 	if explicit := strings.TrimSpace(os.Getenv("FLOPPY_STATE_FILE")); explicit != "" {
 		return explicit
 	}
+	return filepath.Join(floppyCacheDir(), "process-state.json")
+}
+
+// floppyCacheDir is where floppy keeps per-user state and logs.
+func floppyCacheDir() string {
 	cacheDir, err := os.UserCacheDir()
 	if err != nil || cacheDir == "" {
 		home, hErr := os.UserHomeDir()
 		if hErr != nil || home == "" {
-			return filepath.Join(os.TempDir(), "floppy-go", "process-state.json")
+			return filepath.Join(os.TempDir(), "floppy-go")
 		}
 		cacheDir = filepath.Join(home, ".cache")
 	}
-	return filepath.Join(cacheDir, "floppy-go", "process-state.json")
+	return filepath.Join(cacheDir, "floppy-go")
+}
+
+// serviceLogDir is where persisted logs for a service are kept.
+func serviceLogDir(name string) string {
+	return filepath.Join(floppyCacheDir(), "logs", name)
 }
 
 func loadProcessState() ProcessState {
@@ -95,7 +115,7 @@ func isSignalZeroOK(pid int) bool {
 	return err == syscall.EPERM
 }
 
-func stableKeys(entries map[string]ProcessEntry) []string {
+func stableKeys[V any](entries map[string]V) []string {
 	keys := make([]string, 0, len(entries))
 	for k := range entries {
 		keys = append(keys, k)
@@ -115,6 +135,18 @@ func processCmdline(pid int) string {
 	return strings.TrimSpace(string(out))
 }
 
+// parseStartTime converts a recorded `ps -o lstart=` value (e.g.
+// "Mon Oct  6 09:15:02 2025") to a time. ps pads single-digit days, so
+// whitespace is collapsed before parsing.
+func parseStartTime(lstart string) (time.Time, bool) {
+	normalized := strings.Join(strings.Fields(lstart), " ")
+	t, err := time.ParseInLocation("Mon Jan 2 15:04:05 2006", normalized, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
 func processStartTime(pid int) string {
 	if pid <= 0 {
 		return ""
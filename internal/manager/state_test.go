@@ -1,8 +1,11 @@
 package manager
 
 import (
+	"os/exec"
 	"path/filepath"
 	"testing"
+
+	"floppy-go/internal/config"
 )
 
 func Test_stateSaveLoadRoundTrip(t *testing.T) {
@@ -35,6 +38,31 @@ func Test_stateSaveLoadRoundTrip(t *testing.T) {
 	}
 }
 
+func Test_recordStartedProcess_restartsPerSession(t *testing.T) {
+	t.Setenv("FLOPPY_STATE_FILE", filepath.Join(t.TempDir(), "process-state.json"))
+	// An entry from an earlier `floppy up`.
+	if err := saveProcessState(ProcessState{Entries: map[string]ProcessEntry{"api": {Service: "api", PID: 1, Restarts: 7}}}); err != nil {
+		t.Fatal(err)
+	}
+	m := New(&config.Config{}, "")
+	start := func() int {
+		t.Helper()
+		cmd := exec.Command("true")
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer cmd.Wait()
+		m.recordStartedProcess("api", cmd)
+		return loadProcessState().Entries["api"].Restarts
+	}
+	if n := start(); n != 0 {
+		t.Errorf("first start in a session: restarts = %d, want 0", n)
+	}
+	if n := start(); n != 1 {
+		t.Errorf("after one restart: restarts = %d, want 1", n)
+	}
+}
+
 func Test_commandContainsExpected(t *testing.T) {
 	if !commandContainsExpected("/usr/local/bin/poetry run dev", "poetry run dev") {
 		t.Fatalf("expected command match")
@@ -0,0 +1,200 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"floppy-go/internal/config"
+	"floppy-go/internal/tui"
+)
+
+// taskRun is the outcome of a task within one floppy invocation, shared by
+// every service that depends on it so each task runs at most once.
+type taskRun struct {
+	done     chan struct{}
+	exitCode int
+	err      error
+}
+
+// taskOrder returns names and all their task dependencies, dependencies
+// first. Unknown tasks and dependency cycles are errors.
+func taskOrder(cfg *config.Config, names []string) ([]string, error) {
+	order := []string{}
+	state := map[string]int{} // 0 unvisited, 1 visiting, 2 done
+	var visit func(name string, from string) error
+	visit = func(name string, from string) error {
+		task, ok := cfg.Tasks[name]
+		if !ok {
+			if from != "" {
+				return fmt.Errorf("%s depends on unknown task '%s'", from, name)
+			}
+			return fmt.Errorf("task '%s' not found", name)
+		}
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("dependency cycle at task '%s'", name)
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		for _, dep := range task.DependsOn {
+			if err := visit(dep, name); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		order = append(order, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, ""); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// serviceTasks returns the tasks the given services depend on, in run order.
+func (m *Manager) serviceTasks(services []string) ([]string, error) {
+	order := []string{}
+	seen := map[string]bool{}
+	for _, name := range services {
+		deps := m.Config.Services[name].DependsOn
+		for _, dep := range deps {
+			if _, ok := m.Config.Tasks[dep]; !ok {
+				return nil, fmt.Errorf("%s depends on unknown task '%s'", name, dep)
+			}
+		}
+		tasks, err := taskOrder(m.Config, deps)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			if !seen[t] {
+				seen[t] = true
+				order = append(order, t)
+			}
+		}
+	}
+	return order, nil
+}
+
+// runTasks runs each named task (and its dependencies) and stops at the first
+// failure.
+func (m *Manager) runTasks(names []string, logCh chan<- tui.LogLine, statusCh chan<- tui.StatusUpdate) error {
+	for _, name := range names {
+		if err := m.runTask(name, logCh, statusCh); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runTask runs a task once its dependencies succeeded. Output goes to logCh
+// under the task name, or to stdout when logCh is nil. Concurrent callers for
+// the same task wait for the first run and share its result.
+func (m *Manager) runTask(name string, logCh chan<- tui.LogLine, statusCh chan<- tui.StatusUpdate) error {
+	task, ok := m.Config.Tasks[name]
+	if !ok {
+		return fmt.Errorf("task '%s' not found", name)
+	}
+
+	m.procMu.Lock()
+	run, started := m.tasks[name]
+	if !started {
+		run = &taskRun{done: make(chan struct{})}
+		m.tasks[name] = run
+	}
+	m.procMu.Unlock()
+	if started {
+		<-run.done
+		return run.err
+	}
+	defer close(run.done)
+
+	if err := m.runTasks(task.DependsOn, logCh, statusCh); err != nil {
+		run.err = fmt.Errorf("%s: dependency failed: %w", name, err)
+		sendStatus(statusCh, tui.StatusUpdate{Name: name, Status: "error"})
+		return run.err
+	}
+
+	out := func(line string) { fmt.Println(line) }
+	if logCh != nil {
+		out = func(line string) { logCh <- tui.LogLine{Service: name, Text: line} }
+	} else {
+		fmt.Printf("▶ Running task %s\n", name)
+	}
+	sendStatus(statusCh, tui.StatusUpdate{Name: name, Status: "running"})
+
+	run.exitCode, run.err = m.execTask(name, task, out)
+	code := run.exitCode
+	if run.err != nil {
+		sendStatus(statusCh, tui.StatusUpdate{Name: name, Status: "failed", ExitCode: &code})
+		return run.err
+	}
+	sendStatus(statusCh, tui.StatusUpdate{Name: name, Status: "succeeded", ExitCode: &code})
+	return nil
+}
+
+// execTask runs the task command with `sh -c` in its path (relative to the
+// services root) and returns its exit code.
+func (m *Manager) execTask(name string, task config.TaskDef, out func(string)) (int, error) {
+	if task.Command == "" {
+		return -1, fmt.Errorf("task '%s' has no command", name)
+	}
+	cmd := exec.Command("sh", "-c", task.Command)
+	// Tasks rarely have a directory of their own; default to the services root.
+	cmd.Dir = filepath.Join(m.Root, task.Path)
+	cmd.Env = append(os.Environ(), config.MergeEnv(m.Config.Env, task.Env)...)
+	w := &lineWriter{fn: func(line string) { out(m.secrets().redact(line)) }}
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err := cmd.Run()
+	w.Flush()
+	if err == nil {
+		return 0, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), fmt.Errorf("task %s failed (exit %d)", name, exitErr.ExitCode())
+	}
+	return -1, fmt.Errorf("task %s: %w", name, err)
+}
+
+// RunTask runs a single task and its dependencies in the foreground.
+func (m *Manager) RunTask(name string) error {
+	if _, err := taskOrder(m.Config, []string{name}); err != nil {
+		return err
+	}
+	if err := m.runTask(name, nil, nil); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Task %s succeeded\n", name)
+	return nil
+}
+
+// runSetupTasks runs every task marked `setup: true`, in dependency order.
+func (m *Manager) runSetupTasks() error {
+	names := []string{}
+	for name, task := range m.Config.Tasks {
+		if task.Setup {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	order, err := taskOrder(m.Config, names)
+	if err != nil {
+		return err
+	}
+	return m.runTasks(order, nil, nil)
+}
+
+func sendStatus(statusCh chan<- tui.StatusUpdate, update tui.StatusUpdate) {
+	if statusCh != nil {
+		statusCh <- update
+	}
+}
@@ -0,0 +1,72 @@
+package manager
+
+import (
+	"strings"
+	"testing"
+
+	"floppy-go/internal/config"
+	"floppy-go/internal/tui"
+)
+
+func Test_taskOrder(t *testing.T) {
+	cfg := &config.Config{Tasks: map[string]config.TaskDef{
+		"migrate": {},
+		"seed":    {DependsOn: []string{"migrate"}},
+		"codegen": {},
+		"loop-a":  {DependsOn: []string{"loop-b"}},
+		"loop-b":  {DependsOn: []string{"loop-a"}},
+		"broken":  {DependsOn: []string{"missing"}},
+	}}
+
+	got, err := taskOrder(cfg, []string{"seed", "codegen", "migrate"})
+	if err != nil {
+		t.Fatalf("taskOrder: %v", err)
+	}
+	if strings.Join(got, ",") != "migrate,seed,codegen" {
+		t.Errorf("taskOrder = %v", got)
+	}
+	if _, err := taskOrder(cfg, []string{"loop-a"}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("cycle: err = %v", err)
+	}
+	if _, err := taskOrder(cfg, []string{"broken"}); err == nil || !strings.Contains(err.Error(), "unknown task 'missing'") {
+		t.Errorf("unknown dependency: err = %v", err)
+	}
+}
+
+func Test_runTask(t *testing.T) {
+	m := New(&config.Config{Tasks: map[string]config.TaskDef{
+		"ok":   {Command: "echo done"},
+		"fail": {Command: "echo boom >&2; exit 4", DependsOn: []string{"ok"}},
+		"next": {Command: "echo never", DependsOn: []string{"fail"}},
+	}}, "")
+	m.Root = t.TempDir()
+	logCh := make(chan tui.LogLine, 16)
+	statusCh := make(chan tui.StatusUpdate, 16)
+
+	err := m.runTask("next", logCh, statusCh)
+	if err == nil || !strings.Contains(err.Error(), "exit 4") {
+		t.Fatalf("runTask(next): err = %v", err)
+	}
+	close(statusCh)
+	final := map[string]tui.StatusUpdate{}
+	for u := range statusCh {
+		final[u.Name] = u
+	}
+	if u := final["ok"]; u.Status != "succeeded" || u.ExitCode == nil || *u.ExitCode != 0 {
+		t.Errorf("ok: %+v", u)
+	}
+	if u := final["fail"]; u.Status != "failed" || u.ExitCode == nil || *u.ExitCode != 4 {
+		t.Errorf("fail: %+v", u)
+	}
+	if u := final["next"]; u.Status != "error" {
+		t.Errorf("next: %+v", u)
+	}
+
+	// A second run reuses the recorded result instead of running again.
+	if err := m.runTask("ok", logCh, nil); err != nil {
+		t.Errorf("runTask(ok) again: %v", err)
+	}
+	if n := len(logCh); n != 2 {
+		t.Errorf("log lines = %d, want 2 (one per task run)", n)
+	}
+}
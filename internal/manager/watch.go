@@ -0,0 +1,280 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"floppy-go/internal/config"
+	"floppy-go/internal/filewatch"
+	"floppy-go/internal/tui"
+)
+
+// defaultWatchDebounce is used when a service's watch block omits debounce.
+const defaultWatchDebounce = 500 * time.Millisecond
+
+// defaultWatchIgnore is always skipped in addition to watch.ignore.
+var defaultWatchIgnore = []string{
+	".git", "__pycache__", "node_modules", ".venv", ".mypy_cache", ".pytest_cache",
+	"*.pyc", "*.swp", "*.swx", "*~", ".#*", "4913",
+}
+
+// watchServices starts a file watcher for every service in names that has a
+// watch block. The returned func stops them all and waits for a restart in
+// progress to finish, so none outlives the session; it may be called again.
+func (m *Manager) watchServices(names []string) func() {
+	watchers := []*filewatch.Watcher{}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, name := range names {
+		svc := m.Config.Services[name]
+		if svc.Watch == nil {
+			continue
+		}
+		w, err := m.watchService(name, svc, done, &wg)
+		if err != nil {
+			m.run.logCh <- tui.LogLine{Service: "WARN", Text: fmt.Sprintf("%s: file watching disabled: %v", name, err)}
+			continue
+		}
+		watchers = append(watchers, w)
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			for _, w := range watchers {
+				_ = w.Close()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func (m *Manager) watchService(name string, svc config.ServiceDef, done <-chan struct{}, wg *sync.WaitGroup) (*filewatch.Watcher, error) {
+	dir := servicePath(m.Root, name, svc.Path)
+	paths := svc.Watch.Paths
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	roots := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		roots = append(roots, filepath.Clean(p))
+	}
+	patterns := append(append([]string{}, defaultWatchIgnore...), svc.Watch.Ignore...)
+	w, err := filewatch.New(roots, func(path string, isDir bool) bool {
+		return watchIgnored(dir, path, patterns)
+	})
+	if err != nil {
+		return nil, err
+	}
+	debounce := svc.Watch.Debounce
+	if debounce <= 0 {
+		debounce = defaultWatchDebounce
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.debounceRestarts(name, dir, w, debounce, done)
+	}()
+	return w, nil
+}
+
+// debounceRestarts restarts the service once no change has been seen for
+// the debounce interval, until done is closed.
+func (m *Manager) debounceRestarts(name, dir string, w *filewatch.Watcher, debounce time.Duration, done <-chan struct{}) {
+	var timer *time.Timer
+	var fire <-chan time.Time
+	changed := ""
+	for {
+		select {
+		case <-done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case path := <-w.Events():
+			changed = path
+			if timer == nil {
+				timer = time.NewTimer(debounce)
+			} else {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(debounce)
+			}
+			fire = timer.C
+		case <-fire:
+			fire = nil
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if m.watchPaused.Load() {
+				continue
+			}
+			rel, err := filepath.Rel(dir, changed)
+			if err != nil {
+				rel = changed
+			}
+			m.run.logCh <- tui.LogLine{Service: name, Text: fmt.Sprintf("↻ %s changed, restarting %s", rel, name)}
+			if err := m.restartService(name); err != nil {
+				m.run.statusCh <- tui.StatusUpdate{Name: name, Status: "error"}
+				m.run.logCh <- tui.LogLine{Service: "ERROR", Text: fmt.Sprintf("%s: restart failed: %v", name, err)}
+				m.notifier.crash(name, fmt.Sprintf("restart failed: %v", err))
+			}
+		}
+	}
+}
+
+// watchIgnored reports whether path matches one of the ignore patterns.
+// Patterns containing a slash are matched against the path relative to the
+// service directory; others against each path component.
+func watchIgnored(dir, path string, patterns []string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+	parts := strings.Split(rel, "/")
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if strings.Contains(pattern, "/") {
+			if ok, _ := filepath.Match(pattern, rel); ok || strings.HasPrefix(rel, pattern+"/") {
+				return true
+			}
+			continue
+		}
+		for _, part := range parts {
+			if ok, _ := filepath.Match(pattern, part); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// restartService stops the running process for name, waits for it to exit
+// and starts it again with the options Up used. The stop and start hooks run
+// as they would for a manual stop and start. Restarts of one service run one
+// at a time, and the restart fails if the old process group is still alive.
+func (m *Manager) restartService(name string) error {
+	mu := m.restartLock(name)
+	mu.Lock()
+	defer mu.Unlock()
+	svc := m.Config.Services[name]
+	if err := m.runHook(name, svc, hookPreStop, hookLogger(name, hookPreStop, m.run.logCh)); err != nil {
+		return err
+	}
+	if err := m.killTracked(name); err != nil {
+		return err
+	}
+	if err := m.runHook(name, svc, hookPostStop, hookLogger(name, hookPostStop, m.run.logCh)); err != nil {
+		m.run.logCh <- tui.LogLine{Service: "WARN", Text: fmt.Sprintf("%s: %v", name, err)}
+	}
+	return m.startService(name, m.run.detached, m.run.noPTY, m.run.logCh, m.run.statusCh)
+}
+
+func (m *Manager) restartLock(name string) *sync.Mutex {
+	m.procMu.Lock()
+	defer m.procMu.Unlock()
+	if m.restartMu == nil {
+		m.restartMu = map[string]*sync.Mutex{}
+	}
+	mu := m.restartMu[name]
+	if mu == nil {
+		mu = &sync.Mutex{}
+		m.restartMu[name] = mu
+	}
+	return mu
+}
+
+// killTimeout is how long killTracked waits for the process group to go.
+const killTimeout = 5 * time.Second
+
+// killTracked kills the process group started for name in this session and
+// waits (up to killTimeout) for it to exit. It returns an error when the
+// leader or any other process of the group is still alive by then.
+func (m *Manager) killTracked(name string) error {
+	m.procMu.Lock()
+	cmd := m.processes[name]
+	exited := m.exited[name]
+	if cmd != nil && cmd.Process != nil && !closed(exited) {
+		// Only while it runs: an exited process's waitForExit has already
+		// read and cleared the flag, which would leave it set for the next.
+		m.stopping[name] = true
+	}
+	m.procMu.Unlock()
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	pgid := cmd.Process.Pid // services run as their own process group
+	_ = killProcess(pgid)
+	deadline := time.After(killTimeout)
+	if exited != nil {
+		select {
+		case <-exited:
+		case <-deadline:
+			return fmt.Errorf("%s (PID %d) did not exit within %s", name, pgid, killTimeout)
+		}
+	}
+	for groupAlive(pgid) {
+		select {
+		case <-deadline:
+			return fmt.Errorf("processes of %s (group %d) still running after %s", name, pgid, killTimeout)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	return nil
+}
+
+// closed reports whether ch is closed; a nil channel is never closed.
+func closed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// groupAlive reports whether any process of group pgid is left.
+func groupAlive(pgid int) bool {
+	return pgid > 0 && syscall.Kill(-pgid, 0) == nil
+}
+
+// handleRequests applies actions sent from the TUI.
+func (m *Manager) handleRequests(requests <-chan tui.Request) {
+	for req := range requests {
+		switch req.Action {
+		case tui.RequestWatch:
+			m.watchPaused.Store(!req.Enabled)
+			state := "resumed"
+			if !req.Enabled {
+				state = "paused"
+			}
+			m.run.logCh <- tui.LogLine{Service: "INFO", Text: "auto-restart on file changes " + state}
+		case tui.RequestRestart:
+			if _, ok := m.Config.Services[req.Service]; !ok {
+				continue
+			}
+			go func(name string) {
+				m.run.logCh <- tui.LogLine{Service: name, Text: "↻ restarting " + name}
+				if err := m.restartService(name); err != nil {
+					m.run.statusCh <- tui.StatusUpdate{Name: name, Status: "error"}
+					m.run.logCh <- tui.LogLine{Service: "ERROR", Text: fmt.Sprintf("%s: restart failed: %v", name, err)}
+					m.notifier.crash(name, fmt.Sprintf("restart failed: %v", err))
+				}
+			}(req.Service)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package manager
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"floppy-go/internal/config"
+	"floppy-go/internal/filewatch"
+	"floppy-go/internal/tui"
+)
+
+func Test_debounceRestarts_stopsOnDone(t *testing.T) {
+	dir := t.TempDir()
+	w, err := filewatch.New([]string{dir}, func(string, bool) bool { return false })
+	if err != nil {
+		t.Skipf("file watching unavailable: %v", err)
+	}
+	defer w.Close()
+	m := new(Manager)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		m.debounceRestarts("api", dir, w, time.Hour, done)
+		close(exited)
+	}()
+	if err := os.WriteFile(filepath.Join(dir, "app.py"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	close(done)
+	select {
+	case <-exited:
+	case <-time.After(2 * time.Second):
+		t.Fatal("debounceRestarts kept running after done was closed")
+	}
+}
+
+func Test_killTracked_waitsForGroup(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 30 & sleep 30")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	m := New(&config.Config{}, filepath.Join(t.TempDir(), "services.yaml"))
+	m.processes["api"] = cmd
+	m.exited["api"] = exited
+	if err := m.killTracked("api"); err != nil {
+		t.Fatal(err)
+	}
+	if groupAlive(cmd.Process.Pid) {
+		t.Error("process group still alive after killTracked")
+	}
+	if !m.stopping["api"] {
+		t.Error("api should be marked as stopping")
+	}
+}
+
+func Test_waitForExit_crashAfterRestart(t *testing.T) {
+	t.Setenv("FLOPPY_STATE_FILE", filepath.Join(t.TempDir(), "state.json"))
+	var out bytes.Buffer
+	n, err := newNotifier(&config.NotifyConfig{Desktop: "osc9"}, &out, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	n.now = func() time.Time {
+		now = now.Add(notifyInterval) // never rate limited
+		return now
+	}
+	m := New(&config.Config{}, filepath.Join(t.TempDir(), "services.yaml"))
+	m.notifier = n
+	statusCh := make(chan tui.StatusUpdate, 8)
+	crash := func() {
+		t.Helper()
+		cmd := exec.Command("sh", "-c", "exit 3")
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		m.trackProcess("api", cmd)
+		m.waitForExit("api", cmd, nil, statusCh)
+	}
+
+	crash()
+	// Restarting the crashed service kills a process that is already gone.
+	if err := m.killTracked("api"); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	crash()
+	if !strings.Contains(out.String(), "api: exited unexpectedly") {
+		t.Errorf("second crash after a restart should notify, wrote %q", out.String())
+	}
+}
+
+func Test_restartLock_perService(t *testing.T) {
+	m := new(Manager)
+	if m.restartLock("api") != m.restartLock("api") {
+		t.Error("restartLock should return the same mutex for a service")
+	}
+	if m.restartLock("api") == m.restartLock("web") {
+		t.Error("services should not share a restart lock")
+	}
+	var wg sync.WaitGroup
+	mu := m.restartLock("api")
+	mu.Lock()
+	locked := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.restartLock("api").Lock()
+		close(locked)
+		m.restartLock("api").Unlock()
+	}()
+	select {
+	case <-locked:
+		t.Fatal("a second restart of api ran while the first held the lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	mu.Unlock()
+	wg.Wait()
+}
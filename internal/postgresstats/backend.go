@@ -0,0 +1,40 @@
+package postgresstats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TerminateBackend ends the session of backend pid with pg_terminate_backend,
+// rolling back its open transaction.
+func TerminateBackend(ctx context.Context, url string, pid int64) error {
+	return signalBackend(ctx, url, "pg_terminate_backend", pid)
+}
+
+func signalBackend(ctx context.Context, url, fn string, pid int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := sql.Open("postgres", ensureSSLOption(url))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var ok bool
+	if err := db.QueryRowContext(ctx, "SELECT "+fn+"($1)", pid).Scan(&ok); err != nil {
+		return fmt.Errorf("%s(%d): %w", fn, pid, err)
+	}
+	if !ok {
+		return fmt.Errorf("%s(%d): no such backend", fn, pid)
+	}
+	return nil
+}
+
+// CancelBackend cancels the current query of backend pid with
+// pg_cancel_backend; the session and its transaction stay open.
+func CancelBackend(ctx context.Context, url string, pid int64) error {
+	return signalBackend(ctx, url, "pg_cancel_backend", pid)
+}
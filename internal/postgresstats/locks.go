@@ -0,0 +1,199 @@
+package postgresstats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// LockNode is a backend in a lock wait tree: the root holds locks others
+// wait for, and each Blocked entry waits (directly) on its parent.
+type LockNode struct {
+	PID      int64
+	State    string
+	Query    string // truncated query text
+	Duration string // time in the current state
+	AppName  string
+	Waits    []string // lock modes and objects it waits for, e.g. "AccessExclusiveLock on orders"
+	Holds    []string // relation locks it holds
+	Blocked  []*LockNode
+}
+
+// Count is the number of backends waiting below n.
+func (n *LockNode) Count() int {
+	total := 0
+	for _, c := range n.Blocked {
+		total += 1 + c.Count()
+	}
+	return total
+}
+
+// Contains reports whether pid is n or waits somewhere below it.
+func (n *LockNode) Contains(pid int64) bool {
+	if n.PID == pid {
+		return true
+	}
+	for _, c := range n.Blocked {
+		if c.Contains(pid) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchLockTrees builds the blocker trees from pg_blocking_pids (PG 9.6+)
+// and pg_locks.
+func fetchLockTrees(ctx context.Context, db *sql.DB) []*LockNode {
+	rows, err := db.QueryContext(ctx, `
+		SELECT pid, unnest(pg_blocking_pids(pid))
+		FROM pg_stat_activity
+		WHERE cardinality(pg_blocking_pids(pid)) > 0
+	`)
+	if err != nil {
+		return nil
+	}
+	blockers := map[int64][]int64{}
+	var pids []int64
+	seen := map[int64]bool{}
+	for rows.Next() {
+		var pid, blocker int64
+		if err := rows.Scan(&pid, &blocker); err != nil {
+			continue
+		}
+		blockers[pid] = append(blockers[pid], blocker)
+		for _, p := range []int64{pid, blocker} {
+			if !seen[p] {
+				seen[p] = true
+				pids = append(pids, p)
+			}
+		}
+	}
+	rows.Close()
+	if len(blockers) == 0 {
+		return nil
+	}
+
+	nodes := map[int64]*LockNode{}
+	for _, pid := range pids {
+		nodes[pid] = &LockNode{PID: pid}
+	}
+	rows, err = db.QueryContext(ctx, `
+		SELECT pid, COALESCE(state, ''), COALESCE(left(query, $1), ''), COALESCE((now() - state_change)::text, ''), COALESCE(application_name, '')
+		FROM pg_stat_activity
+		WHERE pid = ANY($2)
+	`, maxQueryLen, pq.Array(pids))
+	if err == nil {
+		for rows.Next() {
+			var pid int64
+			var n LockNode
+			var dur string
+			if err := rows.Scan(&pid, &n.State, &n.Query, &dur, &n.AppName); err != nil {
+				continue
+			}
+			if node := nodes[pid]; node != nil {
+				node.State, node.Query, node.Duration, node.AppName = n.State, n.Query, formatDuration(dur), n.AppName
+			}
+		}
+		rows.Close()
+	}
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT l.pid, l.mode, l.granted, COALESCE(c.relname, l.locktype)
+		FROM pg_locks l
+		LEFT JOIN pg_class c ON c.oid = l.relation
+		WHERE l.pid = ANY($1)
+		  AND (NOT l.granted OR (l.locktype = 'relation' AND c.relnamespace <> 'pg_catalog'::regnamespace))
+		ORDER BY l.pid, c.relname
+	`, pq.Array(pids))
+	if err == nil {
+		for rows.Next() {
+			var pid int64
+			var mode, object string
+			var granted bool
+			if err := rows.Scan(&pid, &mode, &granted, &object); err != nil {
+				continue
+			}
+			node := nodes[pid]
+			if node == nil {
+				continue
+			}
+			lock := mode + " on " + object
+			if granted {
+				node.Holds = append(node.Holds, lock)
+			} else {
+				node.Waits = append(node.Waits, lock)
+			}
+		}
+		rows.Close()
+	}
+	return buildLockTrees(blockers, nodes)
+}
+
+// buildLockTrees links waiters under their blockers and returns the roots:
+// blockers that wait on nobody, the most blocking first. A backend blocked
+// by two roots shows under both; cycles (deadlocks about to be broken by
+// Postgres) have no root and are left out.
+func buildLockTrees(blockers map[int64][]int64, nodes map[int64]*LockNode) []*LockNode {
+	waiters := map[int64][]int64{}
+	for pid, bs := range blockers {
+		for _, b := range bs {
+			waiters[b] = append(waiters[b], pid)
+		}
+	}
+	var build func(pid int64, path map[int64]bool) *LockNode
+	build = func(pid int64, path map[int64]bool) *LockNode {
+		src := nodes[pid]
+		if src == nil {
+			src = &LockNode{PID: pid}
+		}
+		n := *src
+		n.Blocked = nil
+		path[pid] = true
+		ws := waiters[pid]
+		sort.Slice(ws, func(i, j int) bool { return ws[i] < ws[j] })
+		for _, w := range ws {
+			if !path[w] {
+				n.Blocked = append(n.Blocked, build(w, path))
+			}
+		}
+		delete(path, pid)
+		return &n
+	}
+	var roots []*LockNode
+	for pid := range waiters {
+		if len(blockers[pid]) == 0 {
+			roots = append(roots, build(pid, map[int64]bool{}))
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		if ci, cj := roots[i].Count(), roots[j].Count(); ci != cj {
+			return ci > cj
+		}
+		return roots[i].PID < roots[j].PID
+	})
+	return roots
+}
+
+// Summary describes n on one line: pid, state, duration and its locks.
+func (n *LockNode) Summary() string {
+	parts := []string{fmt.Sprintf("%d", n.PID)}
+	if n.State != "" {
+		parts = append(parts, n.State)
+	}
+	if n.Duration != "" && n.Duration != "-" {
+		parts = append(parts, n.Duration)
+	}
+	if n.AppName != "" {
+		parts = append(parts, "app="+n.AppName)
+	}
+	if len(n.Waits) > 0 {
+		parts = append(parts, "waits for "+strings.Join(n.Waits, ", "))
+	} else if len(n.Holds) > 0 {
+		parts = append(parts, "holds "+strings.Join(n.Holds, ", "))
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,26 @@
+package postgresstats
+
+import "testing"
+
+func Test_buildLockTrees(t *testing.T) {
+	// 1 blocks 2 and 3, 3 blocks 4; 5 and 6 wait on each other (deadlock).
+	blockers := map[int64][]int64{2: {1}, 3: {1}, 4: {3}, 5: {6}, 6: {5}, 7: {8}}
+	nodes := map[int64]*LockNode{
+		1: {PID: 1, State: "idle in transaction", Holds: []string{"RowExclusiveLock on orders"}},
+		4: {PID: 4, Waits: []string{"AccessExclusiveLock on orders"}},
+	}
+	roots := buildLockTrees(blockers, nodes)
+	if len(roots) != 2 || roots[0].PID != 1 || roots[1].PID != 8 {
+		t.Fatalf("roots = %+v, want 1 then 8", roots)
+	}
+	root := roots[0]
+	if root.Count() != 3 || !root.Contains(4) || root.Contains(5) {
+		t.Errorf("tree under 1: count %d", root.Count())
+	}
+	if got := root.Blocked[1].Blocked[0].Summary(); got != "4  waits for AccessExclusiveLock on orders" {
+		t.Errorf("summary = %q", got)
+	}
+	if got := root.Summary(); got != "1  idle in transaction  holds RowExclusiveLock on orders" {
+		t.Errorf("root summary = %q", got)
+	}
+}
@@ -31,6 +31,9 @@ type Stats struct {
 	DatabaseSize   string       // human-readable size
 	Error          string       // non-empty if fetch failed
 	StuckQueries   []StuckQuery // backends that are idle in tx, long-running, or blocking
+	LockTrees      []*LockNode  // root blockers with the backends waiting on them
+	Sessions       []Session    // client connections, for SessionsByService
+	Services       map[string]ServiceSessions // sessions per service, filled in by the caller
 }
 
 const longQuerySec = 30
@@ -116,6 +119,8 @@ func Fetch(ctx context.Context, url string) Stats {
 
 	// Stuck queries: idle in transaction, long-running active, and blocking
 	out.StuckQueries = fetchStuckQueries(ctx, db)
+	out.LockTrees = fetchLockTrees(ctx, db)
+	out.Sessions = fetchSessions(ctx, db)
 	return out
 }
 
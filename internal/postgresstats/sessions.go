@@ -0,0 +1,88 @@
+package postgresstats
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Session is one client connection from pg_stat_activity.
+type Session struct {
+	PID        int64
+	AppName    string
+	Database   string
+	ClientPort int // -1 for Unix socket connections
+	State      string
+	TxAge      time.Duration // age of the open transaction; 0 when none
+}
+
+// ServiceSessions sums the sessions attributed to one service.
+type ServiceSessions struct {
+	Connections int
+	IdleInTx    int
+	LongestTx   time.Duration
+}
+
+func fetchSessions(ctx context.Context, db *sql.DB) []Session {
+	rows, err := db.QueryContext(ctx, `
+		SELECT pid, COALESCE(application_name, ''), COALESCE(datname, ''), COALESCE(client_port, -1),
+			COALESCE(state, ''), COALESCE(EXTRACT(EPOCH FROM now() - xact_start), 0)::float8
+		FROM pg_stat_activity
+		WHERE backend_type = 'client backend' AND pid != pg_backend_pid()
+	`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	var out []Session
+	for rows.Next() {
+		var s Session
+		var txSec float64
+		if err := rows.Scan(&s.PID, &s.AppName, &s.Database, &s.ClientPort, &s.State, &txSec); err != nil {
+			continue
+		}
+		s.TxAge = time.Duration(txSec * float64(time.Second))
+		out = append(out, s)
+	}
+	return out
+}
+
+// SessionsByService attributes sessions to services and sums them. A
+// session belongs to the service named by its application_name, else to the
+// service owning its client port (portOwner, from the service processes'
+// sockets), else to the service whose database it uses: floppy creates
+// NAME and NAME_test for each service.
+func SessionsByService(sessions []Session, services []string, portOwner map[int]string) map[string]ServiceSessions {
+	known := map[string]bool{}
+	databases := map[string]string{}
+	for _, name := range services {
+		known[name] = true
+		databases[name] = name
+		databases[name+"_test"] = name
+	}
+	out := map[string]ServiceSessions{}
+	for _, s := range sessions {
+		name := ""
+		switch {
+		case known[s.AppName]:
+			name = s.AppName
+		case s.ClientPort > 0 && portOwner[s.ClientPort] != "":
+			name = portOwner[s.ClientPort]
+		default:
+			name = databases[s.Database]
+		}
+		if name == "" {
+			continue
+		}
+		agg := out[name]
+		agg.Connections++
+		if s.State == "idle in transaction" || s.State == "idle in transaction (aborted)" {
+			agg.IdleInTx++
+		}
+		if s.TxAge > agg.LongestTx {
+			agg.LongestTx = s.TxAge
+		}
+		out[name] = agg
+	}
+	return out
+}
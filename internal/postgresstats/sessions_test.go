@@ -0,0 +1,23 @@
+package postgresstats
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_SessionsByService(t *testing.T) {
+	sessions := []Session{
+		{PID: 1, AppName: "api", Database: "other", State: "idle in transaction", TxAge: 90 * time.Second},
+		{PID: 2, AppName: "psql", Database: "api_test", State: "active", TxAge: time.Second},
+		{PID: 3, Database: "shop", ClientPort: 50123, State: "idle"},
+		{PID: 4, Database: "shop", ClientPort: 50999, State: "idle"},
+	}
+	got := SessionsByService(sessions, []string{"api", "worker"}, map[int]string{50123: "worker"})
+	want := map[string]ServiceSessions{
+		"api":    {Connections: 2, IdleInTx: 1, LongestTx: 90 * time.Second},
+		"worker": {Connections: 1},
+	}
+	if len(got) != len(want) || got["api"] != want["api"] || got["worker"] != want["worker"] {
+		t.Errorf("SessionsByService = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,246 @@
+package procstats
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Usage is the summed resource usage of every process in a process group.
+type Usage struct {
+	Processes int     // number of live processes in the group
+	RSSBytes  int64   // resident set size
+	CPUTicks  uint64  // utime + stime in clock ticks
+	CPUPct    float64 // CPU % since the previous sample (0 on first sample)
+	OpenFDs   int     // open file descriptors (only those we may read)
+	Threads   int     // threads across all processes
+}
+
+// clockTicks is USER_HZ; it is 100 on every Linux platform we ship for.
+const clockTicks = 100
+
+var pageSize = int64(os.Getpagesize())
+
+// ErrUnsupported is returned where /proc is not available (e.g. macOS).
+var ErrUnsupported = errors.New("process stats require /proc")
+
+// Supported reports whether /proc is available to read process stats from.
+func Supported() bool {
+	_, err := os.Stat("/proc/self/stat")
+	return err == nil
+}
+
+// procStat holds the fields of /proc/<pid>/stat that we care about.
+type procStat struct {
+	PGRP    int
+	UTime   uint64
+	STime   uint64
+	Threads int
+	RSS     int64 // pages
+}
+
+// parseStat parses the contents of /proc/<pid>/stat. The command name may
+// contain spaces and parentheses, so fields are read after the last ')'.
+func parseStat(data string) (procStat, bool) {
+	end := strings.LastIndexByte(data, ')')
+	if end < 0 || end+2 > len(data) {
+		return procStat{}, false
+	}
+	// fields[0] is field 3 (state) in proc(5) numbering.
+	fields := strings.Fields(data[end+2:])
+	if len(fields) < 22 {
+		return procStat{}, false
+	}
+	var s procStat
+	s.PGRP, _ = strconv.Atoi(fields[2])
+	s.UTime, _ = strconv.ParseUint(fields[11], 10, 64)
+	s.STime, _ = strconv.ParseUint(fields[12], 10, 64)
+	s.Threads, _ = strconv.Atoi(fields[17])
+	s.RSS, _ = strconv.ParseInt(fields[21], 10, 64)
+	return s, true
+}
+
+// GroupPIDs returns the PIDs of all processes whose process group is pgid.
+func GroupPIDs(pgid int) ([]int, error) {
+	members, err := groupMembers(map[int]bool{pgid: true})
+	return members[pgid], err
+}
+
+// groupMembers scans /proc once and returns the PIDs belonging to each of
+// the wanted process groups.
+func groupMembers(wanted map[int]bool) (map[int][]int, error) {
+	out := map[int][]int{}
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return out, ErrUnsupported
+	}
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join("/proc", e.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		if s, ok := parseStat(string(data)); ok && wanted[s.PGRP] {
+			out[s.PGRP] = append(out[s.PGRP], pid)
+		}
+	}
+	return out, nil
+}
+
+// Group sums resource usage for the process group pgid. CPUPct is left at 0;
+// use a Sampler to get CPU % between two reads.
+func Group(pgid int) (Usage, error) {
+	usage, err := Groups([]int{pgid})
+	return usage[pgid], err
+}
+
+// Groups sums resource usage for each process group in pgids with a single
+// pass over /proc. Groups with no live processes are omitted.
+func Groups(pgids []int) (map[int]Usage, error) {
+	wanted := map[int]bool{}
+	for _, pgid := range pgids {
+		if pgid > 0 {
+			wanted[pgid] = true
+		}
+	}
+	out := map[int]Usage{}
+	if len(wanted) == 0 {
+		return out, nil
+	}
+	members, err := groupMembers(wanted)
+	if err != nil {
+		return out, err
+	}
+	for pgid, pids := range members {
+		var u Usage
+		for _, pid := range pids {
+			addProcess(&u, pid)
+		}
+		if u.Processes > 0 {
+			out[pgid] = u
+		}
+	}
+	return out, nil
+}
+
+func addProcess(u *Usage, pid int) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	data, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return
+	}
+	s, ok := parseStat(string(data))
+	if !ok {
+		return
+	}
+	u.Processes++
+	u.CPUTicks += s.UTime + s.STime
+	u.Threads += s.Threads
+	u.RSSBytes += s.RSS * pageSize
+	if fds, err := os.ReadDir(filepath.Join(dir, "fd")); err == nil {
+		u.OpenFDs += len(fds)
+	}
+}
+
+// Sampler keeps the previous CPU reading per process group so repeated calls
+// can report CPU % over the interval between them.
+type Sampler struct {
+	mu   sync.Mutex
+	prev map[int]sample
+}
+
+type sample struct {
+	ticks uint64
+	at    time.Time
+}
+
+func NewSampler() *Sampler {
+	return &Sampler{prev: map[int]sample{}}
+}
+
+// Sample reads usage for pgid and fills in CPUPct relative to the last call.
+func (s *Sampler) Sample(pgid int) (Usage, error) {
+	usage, err := s.sample([]int{pgid}, false)
+	return usage[pgid], err
+}
+
+// SampleAll reads usage for every group in pgids and fills in CPUPct
+// relative to the last call for that group. Groups missing from this sample
+// are forgotten, so the sampler does not grow as services restart.
+func (s *Sampler) SampleAll(pgids []int) (map[int]Usage, error) {
+	return s.sample(pgids, true)
+}
+
+func (s *Sampler) sample(pgids []int, prune bool) (map[int]Usage, error) {
+	usage, err := Groups(pgids)
+	if err != nil {
+		return usage, err
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for pgid, u := range usage {
+		if p, ok := s.prev[pgid]; ok {
+			u.CPUPct = cpuPercent(p.ticks, u.CPUTicks, now.Sub(p.at))
+			usage[pgid] = u
+		}
+		s.prev[pgid] = sample{ticks: u.CPUTicks, at: now}
+	}
+	if prune {
+		for pgid := range s.prev {
+			if _, ok := usage[pgid]; !ok {
+				delete(s.prev, pgid)
+			}
+		}
+	}
+	return usage, nil
+}
+
+func cpuPercent(prevTicks, ticks uint64, elapsed time.Duration) float64 {
+	if elapsed <= 0 || ticks < prevTicks {
+		return 0
+	}
+	used := float64(ticks-prevTicks) / clockTicks
+	return used / elapsed.Seconds() * 100
+}
+
+// FormatUptime renders a duration the way ps/docker do: "45s", "12m",
+// "3h4m", "2d5h"; "-" when d is not positive.
+func FormatUptime(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// FormatBytes returns a short human-readable size (e.g. "312M", "1.4G").
+func FormatBytes(b int64) string {
+	const unit = 1024
+	switch {
+	case b < unit:
+		return strconv.FormatInt(b, 10) + "B"
+	case b < unit*unit:
+		return strconv.FormatInt(b/unit, 10) + "K"
+	case b < unit*unit*unit:
+		return strconv.FormatInt(b/(unit*unit), 10) + "M"
+	default:
+		return strconv.FormatFloat(float64(b)/(unit*unit*unit), 'f', 1, 64) + "G"
+	}
+}
@@ -0,0 +1,96 @@
+package procstats
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func Test_parseStat(t *testing.T) {
+	// Command names may contain spaces and parentheses.
+	data := "4239 (my (odd) cmd) S 4235 4200 4235 0 -1 4194304 85 0 0 0 150 50 0 0 20 0 7 0 35841 2703360 306 18446744073709551615"
+	s, ok := parseStat(data)
+	if !ok {
+		t.Fatal("parseStat: want ok")
+	}
+	if s.PGRP != 4200 || s.UTime != 150 || s.STime != 50 || s.Threads != 7 || s.RSS != 306 {
+		t.Errorf("parseStat: got %+v", s)
+	}
+	if _, ok := parseStat("garbage"); ok {
+		t.Error("parseStat(garbage): want !ok")
+	}
+}
+
+func Test_cpuPercent(t *testing.T) {
+	// 50 ticks over one second is half a core.
+	if got := cpuPercent(100, 150, time.Second); got != 50 {
+		t.Errorf("cpuPercent = %v, want 50", got)
+	}
+	if got := cpuPercent(150, 100, time.Second); got != 0 {
+		t.Errorf("cpuPercent with counter reset = %v, want 0", got)
+	}
+}
+
+func Test_Sampler_SampleAllForgetsGoneGroups(t *testing.T) {
+	if !Supported() {
+		t.Skip("no /proc")
+	}
+	s := NewSampler()
+	s.prev[1<<30] = sample{ticks: 1, at: time.Now()} // a group that exited
+	pgrp := syscall.Getpgrp()
+	if _, err := s.SampleAll([]int{pgrp, 1 << 30}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.prev[1<<30]; ok {
+		t.Error("SampleAll kept the sample of a gone group")
+	}
+	if _, ok := s.prev[pgrp]; !ok {
+		t.Error("SampleAll dropped the sample of a live group")
+	}
+}
+
+func TestFormatUptime(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "-"},
+		{45 * time.Second, "45s"},
+		{12 * time.Minute, "12m"},
+		{3*time.Hour + 4*time.Minute, "3h4m"},
+		{53 * time.Hour, "2d5h"},
+	}
+	for _, tt := range tests {
+		if got := FormatUptime(tt.in); got != tt.want {
+			t.Errorf("FormatUptime(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{512, "512B"},
+		{2048, "2K"},
+		{300 * 1024 * 1024, "300M"},
+		{1536 * 1024 * 1024, "1.5G"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.in); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func Test_parseNetTCP(t *testing.T) {
+	data := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:D431 0100007F:1538 01 00000000:00000000 00:00000000 00000000  1000        0 91234 1 0000000000000000 20 4 30 10 -1
+   1: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 0 1 0000000000000000 100 0 0 10 0
+`
+	got := parseNetTCP(data)
+	if len(got) != 1 || got[91234] != 0xD431 {
+		t.Errorf("parseNetTCP = %v, want inode 91234 on port %d", got, 0xD431)
+	}
+}
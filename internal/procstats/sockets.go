@@ -0,0 +1,100 @@
+package procstats
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// GroupPorts maps the local port of every TCP socket open in one of the
+// process groups pgids to that group. Matching the client_port Postgres
+// reports against it tells which service opened a connection.
+func GroupPorts(pgids []int) (map[int]int, error) {
+	out := map[int]int{}
+	wanted := map[int]bool{}
+	for _, pgid := range pgids {
+		if pgid > 0 {
+			wanted[pgid] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return out, nil
+	}
+	ports := map[uint64]int{}
+	for _, name := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		for inode, port := range parseNetTCP(string(data)) {
+			ports[inode] = port
+		}
+	}
+	if len(ports) == 0 {
+		return out, ErrUnsupported
+	}
+	members, err := groupMembers(wanted)
+	if err != nil {
+		return out, err
+	}
+	for pgid, pids := range members {
+		for _, pid := range pids {
+			for _, inode := range socketInodes(pid) {
+				if port, ok := ports[inode]; ok {
+					out[port] = pgid
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// parseNetTCP maps socket inodes to local ports from the contents of
+// /proc/net/tcp or /proc/net/tcp6.
+func parseNetTCP(data string) map[uint64]int {
+	out := map[uint64]int{}
+	lines := strings.Split(data, "\n")
+	for _, line := range lines[1:] { // header
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+		_, portHex, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		port, err := strconv.ParseUint(portHex, 16, 16)
+		if err != nil {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil || inode == 0 {
+			continue
+		}
+		out[inode] = int(port)
+	}
+	return out
+}
+
+// socketInodes lists the socket inodes among pid's open file descriptors
+// (only those we may read).
+func socketInodes(pid int) []uint64 {
+	dir := filepath.Join("/proc", strconv.Itoa(pid), "fd")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []uint64
+	for _, e := range entries {
+		target, err := os.Readlink(filepath.Join(dir, e.Name()))
+		if err != nil || !strings.HasPrefix(target, "socket:[") {
+			continue
+		}
+		inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]"), 10, 64)
+		if err == nil {
+			out = append(out, inode)
+		}
+	}
+	return out
+}
@@ -0,0 +1,218 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// command is an action run by a key or from the palette. Update runs the
+// first enabled command bound to the action of a pressed key, so an action
+// that does different things per panel has one command per context, each
+// with its own enabled predicate; the order of commands decides which one
+// wins. Commands without a title are not listed in the palette.
+type command struct {
+	binding string // keymap action, empty for palette-only commands
+	title   string
+	run     func(m *Model) tea.Cmd
+	enabled func(m *Model) bool // nil means always
+}
+
+var commands = []command{
+	// The split view's active pane takes the scrolling keys first.
+	paneCommand("next-pane", "Next pane"),
+	paneCommand("prev-pane", "Previous pane"),
+	paneCommand("follow", "Toggle follow"),
+	paneCommand("down", ""),
+	paneCommand("up", ""),
+	paneCommand("page-up", ""),
+	paneCommand("page-down", ""),
+	paneCommand("top", ""),
+	paneCommand("bottom", ""),
+
+	{binding: "palette", run: func(m *Model) tea.Cmd {
+		m.openPalette()
+		return nil
+	}},
+	{binding: "tab-logs", title: "Switch to App Logs", run: func(m *Model) tea.Cmd {
+		m.activeTab = TabAppLogs
+		return nil
+	}},
+	{binding: "tab-postgres", title: "Switch to Postgres", run: func(m *Model) tea.Cmd {
+		m.activeTab = TabPostgres
+		return nil
+	}, enabled: func(m *Model) bool { return m.postgresURL != "" }},
+	{binding: "tab-errors", title: "Switch to Errors", run: func(m *Model) tea.Cmd {
+		m.activeTab = TabErrors
+		return nil
+	}},
+	{binding: "focus", title: "Toggle focus between logs and services", run: func(m *Model) tea.Cmd {
+		m.focusStatus = !m.focusStatus
+		return nil
+	}},
+	{binding: "filter", title: "Filter services by name", run: func(m *Model) tea.Cmd {
+		m.focusStatus = true
+		m.filterMode = true
+		return nil
+	}},
+	{binding: "toggle", title: "Show or hide the selected service's logs", run: func(m *Model) tea.Cmd {
+		m.toggleSelectedFilter()
+		return nil
+	}, enabled: statusFocused},
+	{binding: "select-all", title: "Show all services", run: func(m *Model) tea.Cmd {
+		m.setAllFilters(true)
+		return nil
+	}},
+	{binding: "select-none", title: "Hide all services", run: func(m *Model) tea.Cmd {
+		m.setAllFilters(false)
+		return nil
+	}},
+	{binding: "detail", title: "Toggle service detail", run: func(m *Model) tea.Cmd {
+		if m.serviceInfo != nil {
+			m.closeServiceInfo()
+			return nil
+		}
+		return m.openServiceInfo()
+	}, enabled: statusFocused},
+	{binding: "detail", title: "Jump to the selected error", run: func(m *Model) tea.Cmd {
+		m.jumpToError()
+		return nil
+	}, enabled: func(m *Model) bool { return !m.focusStatus && m.activeTab == TabErrors }},
+	{binding: "close", title: "Close service detail", run: func(m *Model) tea.Cmd {
+		m.closeServiceInfo()
+		return nil
+	}, enabled: func(m *Model) bool { return m.serviceInfo != nil }},
+	{binding: "close", title: "Close the expanded line", run: func(m *Model) tea.Cmd {
+		m.closeDetail()
+		return nil
+	}, enabled: func(m *Model) bool { return m.detail != nil }},
+	{binding: "close", title: "Clear search", run: func(m *Model) tea.Cmd {
+		m.search = nil
+		m.searchText = ""
+		return nil
+	}, enabled: func(m *Model) bool { return m.search != nil }},
+	{binding: "down", run: func(m *Model) tea.Cmd { return m.moveCursor(1) }},
+	{binding: "up", run: func(m *Model) tea.Cmd { return m.moveCursor(-1) }},
+	{binding: "top", run: func(m *Model) tea.Cmd { return m.gotoEdge(true) }},
+	{binding: "bottom", run: func(m *Model) tea.Cmd { return m.gotoEdge(false) }},
+	{binding: "page-up", run: func(m *Model) tea.Cmd { return m.page(true) }},
+	{binding: "page-down", run: func(m *Model) tea.Cmd { return m.page(false) }},
+	{binding: "follow", title: "Toggle follow", run: func(m *Model) tea.Cmd {
+		m.follow = !m.follow
+		if m.follow {
+			m.viewport.GotoBottom()
+		}
+		return nil
+	}, enabled: func(m *Model) bool { return !m.focusStatus && !m.paneFocused() }},
+	{binding: "level", title: "Cycle minimum log level", run: func(m *Model) tea.Cmd {
+		m.minLevel = nextLevelFilter(m.minLevel)
+		return nil
+	}},
+	{binding: "search", title: "Search logs", run: func(m *Model) tea.Cmd {
+		m.focusStatus = false
+		m.searchMode = true
+		if m.search != nil {
+			m.searchText = m.search.pattern
+		}
+		return nil
+	}, enabled: func(m *Model) bool { return m.activeTab == TabAppLogs }},
+	{binding: "next-match", title: "Next search match", run: func(m *Model) tea.Cmd {
+		m.searchStep(1)
+		return nil
+	}, enabled: searching},
+	{binding: "prev-match", title: "Previous search match", run: func(m *Model) tea.Cmd {
+		m.searchStep(-1)
+		return nil
+	}, enabled: searching},
+	{binding: "times", title: "Toggle receive times", run: func(m *Model) tea.Cmd {
+		m.showTimes = !m.showTimes
+		m.renderViewport()
+		return nil
+	}},
+	{binding: "jump", title: "Jump to a time", run: func(m *Model) tea.Cmd {
+		m.focusStatus = false
+		m.jumpMode = true
+		m.jumpErr = ""
+		return nil
+	}},
+	{binding: "expand", title: "Expand the selected line", run: func(m *Model) tea.Cmd {
+		m.toggleDetail()
+		return nil
+	}, enabled: func(m *Model) bool { return !m.focusStatus && m.activeTab == TabAppLogs && !m.split }},
+	{binding: "copy", title: "Copy visible logs", run: func(m *Model) tea.Cmd {
+		m.copyVisibleLogs()
+		return nil
+	}, enabled: func(m *Model) bool { return !m.focusStatus }},
+	{binding: "copy-traceback", title: "Copy the selected traceback", run: func(m *Model) tea.Cmd {
+		m.copyError()
+		return nil
+	}, enabled: func(m *Model) bool { return !m.focusStatus && m.activeTab == TabErrors }},
+	{binding: "pg-cancel", title: "Cancel the selected Postgres query", run: func(m *Model) tea.Cmd {
+		if q := m.selectedStuckQuery(); q != nil {
+			m.confirmPgAction(*q, false)
+		}
+		return nil
+	}, enabled: postgresFocused},
+	{binding: "pg-terminate", title: "Terminate the selected Postgres backend", run: func(m *Model) tea.Cmd {
+		if q := m.selectedStuckQuery(); q != nil {
+			m.confirmPgAction(*q, true)
+		}
+		return nil
+	}, enabled: postgresFocused},
+	{binding: "pg-root", title: "Terminate the root Postgres lock blocker", run: func(m *Model) tea.Cmd {
+		if root := m.rootBlocker(); root != nil {
+			m.confirmTerminateRoot(root)
+		}
+		return nil
+	}, enabled: postgresFocused},
+	{binding: "pin", title: "Pin the selected service to a pane", run: func(m *Model) tea.Cmd {
+		m.togglePin()
+		return nil
+	}, enabled: statusFocused},
+	{binding: "split", title: "Toggle split view", run: func(m *Model) tea.Cmd {
+		m.toggleSplit()
+		return nil
+	}},
+	{binding: "export", title: "Export filtered logs to a file", run: func(m *Model) tea.Cmd {
+		m.exportView()
+		return nil
+	}},
+	{binding: "export-all", title: "Export all logs as JSON lines", run: func(m *Model) tea.Cmd {
+		m.exportJSON()
+		return nil
+	}},
+	{binding: "watch", title: "Pause or resume auto-restart", run: func(m *Model) tea.Cmd {
+		m.watchPaused = !m.watchPaused
+		m.sendRequest(Request{Action: RequestWatch, Enabled: !m.watchPaused})
+		return nil
+	}, enabled: func(m *Model) bool { return m.watching }},
+	{binding: "help", title: "Show key bindings", run: func(m *Model) tea.Cmd {
+		m.toggleHelp()
+		return nil
+	}},
+	{binding: "quit", title: "Quit", run: func(m *Model) tea.Cmd {
+		m.interrupted = true
+		return tea.Quit
+	}},
+}
+
+// paneCommand runs action on the active pane while the split view has the
+// keys.
+func paneCommand(action, title string) command {
+	return command{binding: action, title: title, run: func(m *Model) tea.Cmd {
+		return m.paneKey(action)
+	}, enabled: (*Model).paneFocused}
+}
+
+func statusFocused(m *Model) bool { return m.focusStatus }
+
+func searching(m *Model) bool { return !m.focusStatus && m.search != nil }
+
+func postgresFocused(m *Model) bool { return !m.focusStatus && m.activeTab == TabPostgres }
+
+// boundCommand is the first enabled command bound to action, if any.
+func (m *Model) boundCommand(action string) *command {
+	for i := range commands {
+		c := &commands[i]
+		if c.binding == action && (c.enabled == nil || c.enabled(m)) {
+			return c
+		}
+	}
+	return nil
+}
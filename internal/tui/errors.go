@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxTracebackLines caps how much of one traceback is kept.
+const maxTracebackLines = 300
+
+var (
+	tracebackStartRe = regexp.MustCompile(`Traceback \(most recent call last\):\s*$`)
+	// Banners between chained exceptions.
+	tracebackChainRe = regexp.MustCompile(`^(During handling of the above exception|The above exception was the direct cause)`)
+	// Final line of a traceback: "pkg.module.SomeError: message".
+	exceptionLineRe = regexp.MustCompile(`^([A-Za-z_][\w.]*)(?::|$)`)
+	// Exception-like names inside a plain error line.
+	exceptionNameRe = regexp.MustCompile(`\b([A-Z]\w*(?:Error|Exception))\b`)
+)
+
+// errorOccurrence is one traceback or error line as it appeared in the logs.
+type errorOccurrence struct {
+	seq   uint64 // LogLine.seq of the first line
+	lines []string
+	at    time.Time
+}
+
+// errorGroup aggregates occurrences of one exception type in one service.
+type errorGroup struct {
+	Service  string
+	Type     string
+	Count    int
+	LastSeen time.Time
+	last     *errorOccurrence
+}
+
+// tracebackState follows a service's output while a traceback is printed.
+type tracebackState struct {
+	occ     *errorOccurrence
+	key     string // group the occurrence is counted in; "" while collecting
+	inTrace bool   // between "Traceback" and the exception line
+}
+
+// errorLog groups tracebacks and error lines by service and exception type.
+// Lines are fed per service so tracebacks interleaved with other services'
+// output are reassembled.
+type errorLog struct {
+	groups map[string]*errorGroup
+	open   map[string]*tracebackState
+}
+
+func newErrorLog() *errorLog {
+	return &errorLog{groups: map[string]*errorGroup{}, open: map[string]*tracebackState{}}
+}
+
+// observe feeds one log line.
+func (e *errorLog) observe(line LogLine) {
+	at := line.Time
+	text := strings.TrimRight(stripANSI(line.Text), "\r")
+	st := e.open[line.Service]
+
+	if tracebackStartRe.MatchString(text) {
+		switch {
+		case st != nil && st.key != "" && !st.inTrace:
+			// Chained exception, or the error line logged right before the
+			// traceback: keep one occurrence and count it once at the end.
+			e.uncount(st)
+		case st == nil || !st.inTrace:
+			st = &tracebackState{occ: &errorOccurrence{seq: line.seq}}
+			e.open[line.Service] = st
+		}
+		st.inTrace = true
+		st.occ.add(text, at)
+		return
+	}
+	if st != nil && st.inTrace {
+		if text == "" || text[0] == ' ' || text[0] == '\t' {
+			st.occ.add(text, at)
+			return
+		}
+		if m := exceptionLineRe.FindStringSubmatch(text); m != nil {
+			st.occ.add(text, at)
+			st.inTrace = false
+			e.count(st, line.Service, m[1])
+			return
+		}
+		// Something else interrupted the traceback; keep what we have.
+		st.inTrace = false
+		e.count(st, line.Service, "Traceback")
+	}
+	if st != nil && st.key != "" && (text == "" || tracebackChainRe.MatchString(text)) {
+		st.occ.add(text, at)
+		return
+	}
+
+	delete(e.open, line.Service)
+	if line.Level != LevelError || line.Service == "ERROR" {
+		return
+	}
+	typ := "error"
+	if m := exceptionNameRe.FindStringSubmatch(text); m != nil {
+		typ = m[1]
+	}
+	st = &tracebackState{occ: &errorOccurrence{seq: line.seq}}
+	st.occ.add(text, at)
+	e.count(st, line.Service, typ)
+	e.open[line.Service] = st
+}
+
+func (o *errorOccurrence) add(text string, at time.Time) {
+	if len(o.lines) < maxTracebackLines {
+		o.lines = append(o.lines, text)
+	}
+	o.at = at
+}
+
+func (e *errorLog) count(st *tracebackState, service, typ string) {
+	key := service + "\x00" + typ
+	g, ok := e.groups[key]
+	if !ok {
+		g = &errorGroup{Service: service, Type: typ}
+		e.groups[key] = g
+	}
+	g.Count++
+	g.LastSeen = st.occ.at
+	g.last = st.occ
+	st.key = key
+}
+
+// uncount takes st's occurrence back out of its group so it can be counted
+// again under the type of the exception that ends it.
+func (e *errorLog) uncount(st *tracebackState) {
+	if g, ok := e.groups[st.key]; ok {
+		g.Count--
+		if g.Count <= 0 {
+			delete(e.groups, st.key)
+		}
+	}
+	st.key = ""
+}
+
+// sorted returns the groups, most recently seen first.
+func (e *errorLog) sorted() []*errorGroup {
+	groups := make([]*errorGroup, 0, len(e.groups))
+	for _, g := range e.groups {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if !groups[i].LastSeen.Equal(groups[j].LastSeen) {
+			return groups[i].LastSeen.After(groups[j].LastSeen)
+		}
+		if groups[i].Service != groups[j].Service {
+			return groups[i].Service < groups[j].Service
+		}
+		return groups[i].Type < groups[j].Type
+	})
+	return groups
+}
+
+func (e *errorLog) total() int {
+	n := 0
+	for _, g := range e.groups {
+		n += g.Count
+	}
+	return n
+}
+
+func (m *Model) selectedErrorGroup() *errorGroup {
+	groups := m.errorLog.sorted()
+	if len(groups) == 0 {
+		return nil
+	}
+	if m.errSelected >= len(groups) {
+		m.errSelected = len(groups) - 1
+	}
+	if m.errSelected < 0 {
+		m.errSelected = 0
+	}
+	return groups[m.errSelected]
+}
+
+func (m *Model) moveErrorSelection(delta int) {
+	m.errSelected += delta
+	m.selectedErrorGroup()
+}
+
+// jumpToError switches to App Logs with the last occurrence of the selected
+// group at the top of the view, making sure its service and level are shown.
+func (m *Model) jumpToError() {
+	g := m.selectedErrorGroup()
+	if g == nil {
+		return
+	}
+	m.filters[g.Service] = true
+	m.minLevel = LevelUnknown
+	m.activeTab = TabAppLogs
+	m.follow = false
+	m.renderViewport()
+	for row, idx := range m.lineIndex {
+		if m.logs[idx].seq == g.last.seq {
+			m.viewport.SetYOffset(row)
+			return
+		}
+	}
+}
+
+// copyError copies the full text of the selected group's last occurrence.
+func (m *Model) copyError() {
+	if g := m.selectedErrorGroup(); g != nil {
+		_ = clipboard.WriteAll(strings.TrimRight(strings.Join(g.last.lines, "\n"), "\n"))
+	}
+}
+
+// renderErrorsViewport lists error groups and shows the last traceback of
+// the selected one below the list.
+func (m *Model) renderErrorsViewport() {
+	width := m.errViewport.Width
+	if width < 40 {
+		width = 80
+	}
+	groups := m.errorLog.sorted()
+	if len(groups) == 0 {
+		m.errViewport.SetContent(lipgloss.NewStyle().Foreground(m.theme.OK).Render("No errors or tracebacks yet."))
+		return
+	}
+	m.selectedErrorGroup()
+
+	lineStyle := lipgloss.NewStyle().MaxWidth(width)
+	dim := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%-16s %-32s %6s  %s", "Service", "Type", "Count", "Last seen")),
+	}
+	for i, g := range groups {
+		row := fmt.Sprintf("%-16s %-32s %6d  %s", g.Service, g.Type, g.Count, g.LastSeen.Format("15:04:05"))
+		if i == m.errSelected {
+			row = lipgloss.NewStyle().Reverse(true).Render(row)
+		}
+		lines = append(lines, lineStyle.Render(row))
+	}
+	selectedRow := m.errSelected + 1
+
+	g := groups[m.errSelected]
+	lines = append(lines, "", dim.Render(fmt.Sprintf("─ last %s in %s ─ enter jump to logs • y copy", g.Type, g.Service)))
+	red := lipgloss.NewStyle().Foreground(m.theme.Error)
+	for _, l := range g.last.lines {
+		for _, chunk := range wrapRunes(l, width) {
+			lines = append(lines, red.Render(chunk))
+		}
+	}
+	m.errViewport.SetContent(strings.Join(lines, "\n"))
+	if selectedRow < m.errViewport.YOffset {
+		m.errViewport.SetYOffset(selectedRow)
+	} else if selectedRow >= m.errViewport.YOffset+m.errViewport.Height {
+		m.errViewport.SetYOffset(selectedRow - m.errViewport.Height + 1)
+	}
+}
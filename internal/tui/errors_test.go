@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_errorLog_groupsInterleavedTracebacks(t *testing.T) {
+	m := NewModel(nil, nil, nil, Options{})
+	m.viewport.Width = 200
+	m.viewport.Height = 5
+	feed := []LogLine{
+		{Service: "api", Text: "ERROR:root:request failed"},
+		{Service: "api", Text: "Traceback (most recent call last):"},
+		{Service: "worker", Text: "INFO: task received"},
+		{Service: "api", Text: `  File "app.py", line 3, in handler`},
+		{Service: "api", Text: "    raise ValueError(\"bad\")"},
+		{Service: "api", Text: "ValueError: bad"},
+		{Service: "api", Text: "INFO: still serving"},
+		{Service: "worker", Text: "Traceback (most recent call last):"},
+		{Service: "worker", Text: `  File "tasks.py", line 9, in run`},
+		{Service: "worker", Text: "KeyError: 'id'"},
+		{Service: "worker", Text: ""},
+		{Service: "worker", Text: "During handling of the above exception, another exception occurred:"},
+		{Service: "worker", Text: ""},
+		{Service: "worker", Text: "Traceback (most recent call last):"},
+		{Service: "worker", Text: `  File "tasks.py", line 11, in run`},
+		{Service: "worker", Text: "RuntimeError: lookup failed"},
+		{Service: "api", Text: "ERROR: connection refused (ConnectionError)"},
+		{Service: "api", Text: "ERROR:root:request failed"},
+		{Service: "api", Text: "Traceback (most recent call last):"},
+		{Service: "api", Text: `  File "app.py", line 3, in handler`},
+		{Service: "api", Text: "ValueError: worse"},
+	}
+	for _, line := range feed {
+		m.appendLog(line)
+	}
+
+	got := map[string]int{}
+	for _, g := range m.errorLog.sorted() {
+		got[g.Service+" "+g.Type] = g.Count
+	}
+	want := map[string]int{
+		"api ValueError":      2,
+		"worker RuntimeError": 1,
+		"api ConnectionError": 1,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("groups = %v, want %v", got, want)
+	}
+	for k, n := range want {
+		if got[k] != n {
+			t.Errorf("%s: count = %d, want %d (all: %v)", k, got[k], n, got)
+		}
+	}
+
+	g := m.errorLog.groups["worker\x00RuntimeError"]
+	text := strings.Join(g.last.lines, "\n")
+	for _, part := range []string{"KeyError: 'id'", "During handling", "RuntimeError: lookup failed"} {
+		if !strings.Contains(text, part) {
+			t.Errorf("chained traceback missing %q:\n%s", part, text)
+		}
+	}
+	if strings.Contains(text, "task received") {
+		t.Errorf("traceback picked up another line:\n%s", text)
+	}
+
+	// Jumping lands on the first line of the last occurrence.
+	m.activeTab = TabErrors
+	m.errSelected = 0 // most recent: api ValueError
+	m.jumpToError()
+	if m.activeTab != TabAppLogs || m.follow {
+		t.Error("jump should switch to App Logs with follow off")
+	}
+	m.viewport.Height = 2
+	m.jumpToError()
+	if idx := m.lineIndex[m.viewport.YOffset]; m.logs[idx].seq != 18 {
+		t.Errorf("jumped to %q (seq %d), want seq 18", m.logs[idx].Text, m.logs[idx].seq)
+	}
+}
+
+func Test_errorLog_sortedByLastSeen(t *testing.T) {
+	e := newErrorLog()
+	now := time.Now()
+	e.observe(LogLine{Service: "a", Text: "boom", Level: LevelError, Time: now, seq: 1})
+	e.observe(LogLine{Service: "b", Text: "boom", Level: LevelError, Time: now.Add(time.Second), seq: 2})
+	groups := e.sorted()
+	if len(groups) != 2 || groups[0].Service != "b" {
+		t.Errorf("sorted = %+v", groups)
+	}
+	if e.total() != 2 {
+		t.Errorf("total = %d", e.total())
+	}
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// noticeTTL is how long a notice stays in the footer.
+const noticeTTL = 5 * time.Second
+
+// exportRecord is one line of a JSONL export.
+type exportRecord struct {
+	Time    time.Time `json:"time"`
+	Service string    `json:"service"`
+	Level   string    `json:"level,omitempty"`
+	Text    string    `json:"text"`
+}
+
+// viewLines returns the log lines the combined view shows: enabled services
+// and the level filter, as in renderViewport.
+func (m *Model) viewLines() []LogLine {
+	out := []LogLine{}
+	for _, line := range m.logs {
+		if m.serviceShown(line.Service) && m.levelShown(line) {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// exportView writes the filtered view as plain text ("[service] text", with
+// the receive time first when times are shown).
+func (m *Model) exportView() {
+	lines := m.viewLines()
+	path, err := writeExport(m.exportDir, "log", func(w *bufio.Writer) {
+		for _, line := range lines {
+			if m.showTimes {
+				w.WriteString(line.Time.Format("2006-01-02 15:04:05.000") + " ")
+			}
+			fmt.Fprintf(w, "[%s] %s\n", line.Service, stripANSI(line.Text))
+		}
+	})
+	m.exportNotice(len(lines), path, err)
+}
+
+// exportJSON writes the whole buffer, unfiltered, as JSON lines.
+func (m *Model) exportJSON() {
+	path, err := writeExport(m.exportDir, "jsonl", func(w *bufio.Writer) {
+		enc := json.NewEncoder(w)
+		for _, line := range m.logs {
+			_ = enc.Encode(exportRecord{
+				Time:    line.Time,
+				Service: line.Service,
+				Level:   line.Level.String(),
+				Text:    stripANSI(line.Text),
+			})
+		}
+	})
+	m.exportNotice(len(m.logs), path, err)
+}
+
+// writeExport creates floppy-logs-<timestamp>.<ext> in dir (the working
+// directory when empty) and fills it with write.
+func writeExport(dir, ext string, write func(*bufio.Writer)) (string, error) {
+	if dir == "" {
+		dir = "."
+	}
+	path := filepath.Join(dir, fmt.Sprintf("floppy-logs-%s.%s", time.Now().Format("20060102-150405"), ext))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	w := bufio.NewWriter(f)
+	write(w)
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return path, nil
+}
+
+func (m *Model) exportNotice(n int, path string, err error) {
+	if err != nil {
+		m.setNotice("export failed: " + err.Error())
+		return
+	}
+	m.setNotice(fmt.Sprintf("exported %d lines to %s", n, path))
+}
+
+// setNotice shows a short message in the footer for noticeTTL.
+func (m *Model) setNotice(text string) {
+	m.notice = text
+	m.noticeAt = time.Now()
+}
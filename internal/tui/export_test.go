@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportModel(t *testing.T) *Model {
+	t.Helper()
+	m := NewModel(nil, nil, nil, Options{ExportDir: t.TempDir()})
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	m.appendLog(LogLine{Service: "api", Text: "\x1b[32mready\x1b[0m", Time: at})
+	m.appendLog(LogLine{Service: "api", Text: "ERROR boom", Time: at})
+	m.appendLog(LogLine{Service: "portal", Text: "compiled", Time: at})
+	m.appendLog(LogLine{Service: "worker", Text: "tick", Time: at})
+	return m
+}
+
+func exportedFile(t *testing.T, m *Model, ext string) string {
+	t.Helper()
+	matches, _ := filepath.Glob(filepath.Join(m.exportDir, "floppy-logs-*."+ext))
+	if len(matches) != 1 {
+		t.Fatalf("exports = %v (notice %q)", matches, m.notice)
+	}
+	if !strings.Contains(m.notice, matches[0]) {
+		t.Errorf("notice = %q", m.notice)
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func Test_exportView_filtered(t *testing.T) {
+	m := exportModel(t)
+	m.filters["worker"] = false
+	m.filterText = "api" // narrows the status rows, not the view
+	m.exportView()
+	got := exportedFile(t, m, "log")
+	want := "[api] ready\n[api] ERROR boom\n[portal] compiled\n"
+	if got != want {
+		t.Errorf("export = %q, want %q", got, want)
+	}
+
+	m.minLevel = LevelError
+	if lines := m.viewLines(); len(lines) != 1 || lines[0].Text != "ERROR boom" {
+		t.Errorf("viewLines at error level = %+v", lines)
+	}
+}
+
+func Test_exportJSON_fullBuffer(t *testing.T) {
+	m := exportModel(t)
+	m.filters["worker"] = false
+	m.minLevel = LevelError
+	m.exportJSON()
+	got := exportedFile(t, m, "jsonl")
+
+	var records []exportRecord
+	scanner := bufio.NewScanner(strings.NewReader(got))
+	for scanner.Scan() {
+		var rec exportRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("bad line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 4 {
+		t.Fatalf("records = %d, want the whole buffer", len(records))
+	}
+	if r := records[0]; r.Service != "api" || r.Text != "ready" || r.Time.Year() != 2025 {
+		t.Errorf("first record = %+v", r)
+	}
+	if records[1].Level != "ERROR" {
+		t.Errorf("level = %q", records[1].Level)
+	}
+}
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonField is one top-level key of a JSON log line, in source order.
+type jsonField struct {
+	Key   string
+	Value json.RawMessage
+}
+
+var (
+	jsonTimeKeys  = []string{"time", "timestamp", "ts", "@timestamp", "asctime"}
+	jsonLevelKeys = []string{"level", "levelname", "severity", "lvl", "log.level"}
+	jsonMsgKeys   = []string{"msg", "message", "event"}
+)
+
+// parseJSONLog decodes a JSON object log line keeping the key order.
+func parseJSONLog(text string) ([]jsonField, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "{") || !strings.HasSuffix(text, "}") {
+		return nil, false
+	}
+	dec := json.NewDecoder(strings.NewReader(text))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+	fields := []jsonField{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, false
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, false
+		}
+		fields = append(fields, jsonField{Key: key, Value: raw})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// compactJSONLog renders fields as "time LEVEL msg key=value ..." and returns
+// the byte length of the leading time (0 when there is none).
+func compactJSONLog(fields []jsonField) (string, int) {
+	used := map[string]bool{}
+	take := func(keys []string) string {
+		for _, k := range keys {
+			for _, f := range fields {
+				if f.Key == k && !used[k] {
+					used[k] = true
+					return jsonScalar(f.Value)
+				}
+			}
+		}
+		return ""
+	}
+
+	parts := []string{}
+	stamp := take(jsonTimeKeys)
+	if stamp != "" {
+		parts = append(parts, stamp)
+	}
+	if level := take(jsonLevelKeys); level != "" {
+		if l := parseLevelName(level); l != LevelUnknown {
+			level = l.String()
+		}
+		parts = append(parts, strings.ToUpper(level))
+	}
+	if msg := take(jsonMsgKeys); msg != "" {
+		parts = append(parts, msg)
+	}
+	for _, f := range fields {
+		if used[f.Key] {
+			continue
+		}
+		v := jsonScalar(f.Value)
+		if strings.ContainsAny(v, " \t\"=") && !strings.HasPrefix(v, "{") && !strings.HasPrefix(v, "[") {
+			v = fmt.Sprintf("%q", v)
+		}
+		parts = append(parts, f.Key+"="+v)
+	}
+	return strings.Join(parts, " "), len(stamp)
+}
+
+// jsonScalar renders a JSON value without string quotes; objects and arrays
+// are compacted.
+func jsonScalar(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err == nil {
+		return buf.String()
+	}
+	return string(raw)
+}
+
+// prettyJSON indents a JSON log line for the detail pane.
+func prettyJSON(text string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(strings.TrimSpace(text)), "", "  "); err != nil {
+		return text
+	}
+	return buf.String()
+}
@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_compactJSONLog(t *testing.T) {
+	fields, ok := parseJSONLog(`{"ts":"2025-01-02T10:11:12Z","msg":"request done","level":"warning","path":"/api/x","user":{"id":7},"note":"a b"}`)
+	if !ok {
+		t.Fatal("parseJSONLog: want ok")
+	}
+	got, stamp := compactJSONLog(fields)
+	want := `2025-01-02T10:11:12Z WARN request done path=/api/x user={"id":7} note="a b"`
+	if got != want {
+		t.Errorf("compactJSONLog = %q, want %q", got, want)
+	}
+	if stamp != len("2025-01-02T10:11:12Z") {
+		t.Errorf("stamp = %d", stamp)
+	}
+
+	for _, text := range []string{"plain text", `{"unterminated": `, `["array"]`, `{"a":1} trailing`} {
+		if _, ok := parseJSONLog(text); ok {
+			t.Errorf("parseJSONLog(%q): want !ok", text)
+		}
+	}
+}
+
+func Test_rowsText_rawJSON(t *testing.T) {
+	m := NewModel(nil, nil, nil, Options{})
+	m.viewport.Width = 200
+	raw := `{"level":"info","msg":"hello","n":1}`
+	m.appendLog(LogLine{Service: "api", Text: "plain line"})
+	m.appendLog(LogLine{Service: "api", Text: raw})
+	m.renderViewport()
+
+	if !strings.Contains(m.lastLogContent, "INFO hello n=1") {
+		t.Errorf("JSON line should render compact:\n%s", stripANSI(m.lastLogContent))
+	}
+	got := m.rowsText(0, 1)
+	want := "[api] plain line\n[api] " + raw
+	if got != want {
+		t.Errorf("rowsText = %q, want %q", got, want)
+	}
+}
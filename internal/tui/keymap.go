@@ -0,0 +1,234 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyBinding is a remappable action and the keys that trigger it. Update
+// dispatches on the action a pressed key resolves to.
+type keyBinding struct {
+	action string
+	keys   []string
+	help   string
+}
+
+// defaultBindings lists the actions in the order the help overlay shows them.
+// No two actions share a default key.
+var defaultBindings = []keyBinding{
+	{"quit", []string{"q"}, "quit (ctrl+c also quits)"},
+	{"help", []string{"?"}, "show or hide this help"},
+	{"palette", []string{":", "ctrl+p"}, "command palette"},
+	{"tab-logs", []string{"1"}, "App Logs tab"},
+	{"tab-postgres", []string{"2"}, "Postgres tab"},
+	{"tab-errors", []string{"3"}, "Errors tab"},
+	{"focus", []string{"tab"}, "switch focus between logs and services"},
+	{"filter", []string{"/"}, "filter services by name"},
+	{"toggle", []string{" "}, "show or hide the selected service's logs"},
+	{"select-all", []string{"a"}, "show all services"},
+	{"select-none", []string{"h"}, "hide all services"},
+	{"detail", []string{"enter"}, "service detail / jump to error"},
+	{"close", []string{"esc"}, "close panel or clear search"},
+	{"down", []string{"j", "down"}, "scroll or select down"},
+	{"up", []string{"k", "up"}, "scroll or select up"},
+	{"top", []string{"g", "home"}, "go to top"},
+	{"bottom", []string{"G", "end"}, "go to bottom"},
+	{"page-up", []string{"pgup"}, "page up"},
+	{"page-down", []string{"pgdown"}, "page down"},
+	{"follow", []string{"f"}, "follow new lines"},
+	{"level", []string{"L"}, "cycle the minimum log level"},
+	{"times", []string{"t"}, "show receive times"},
+	{"jump", []string{"T"}, "jump to a time"},
+	{"search", []string{"s"}, "search logs"},
+	{"next-match", []string{"n"}, "next search match"},
+	{"prev-match", []string{"N"}, "previous search match"},
+	{"expand", []string{"e"}, "expand the selected line"},
+	{"copy", []string{"Y"}, "copy visible logs"},
+	{"copy-traceback", []string{"y"}, "copy the selected traceback"},
+	{"pg-cancel", []string{"c"}, "cancel the selected Postgres query"},
+	{"pg-terminate", []string{"K"}, "terminate the selected Postgres backend"},
+	{"pg-root", []string{"B"}, "terminate the root Postgres lock blocker"},
+	{"export", []string{"x"}, "export the filtered view"},
+	{"export-all", []string{"X"}, "export all lines as JSON"},
+	{"pin", []string{"p"}, "pin the selected service to a pane"},
+	{"split", []string{"v"}, "toggle the split view"},
+	{"next-pane", []string{"]"}, "next pane"},
+	{"prev-pane", []string{"["}, "previous pane"},
+	{"watch", []string{"w"}, "pause or resume auto-restart"},
+}
+
+// KeyMap holds the active bindings after applying user remaps.
+type KeyMap struct {
+	bindings []keyBinding
+	actions  map[string]string // key -> action it triggers
+}
+
+// ParseKeys applies overrides (action -> comma separated keys, e.g.
+// "quit: ctrl+q" or "down: j, ctrl+n") to the default bindings. A remapped
+// action no longer answers to its default keys.
+func ParseKeys(overrides map[string]string) (*KeyMap, error) {
+	known := map[string]bool{}
+	for _, b := range defaultBindings {
+		known[b.action] = true
+	}
+	for action := range overrides {
+		if !known[action] {
+			return nil, fmt.Errorf("unknown action %q", action)
+		}
+	}
+
+	var bindings []keyBinding
+	for _, b := range defaultBindings {
+		value, ok := overrides[b.action]
+		if !ok {
+			bindings = append(bindings, b)
+			continue
+		}
+		keys := splitKeys(value)
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("%s: no keys given", b.action)
+		}
+		bindings = append(bindings, keyBinding{action: b.action, keys: keys, help: b.help})
+	}
+	actions, err := keyActions(bindings, overrides)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyMap{bindings: bindings, actions: actions}, nil
+}
+
+// keyActions maps every key of bindings to its action and rejects keys bound
+// to two actions. The error names the remapped action when there is one.
+func keyActions(bindings []keyBinding, overrides map[string]string) (map[string]string, error) {
+	actions := map[string]string{}
+	for _, b := range bindings {
+		for _, k := range b.keys {
+			other, ok := actions[k]
+			if !ok || other == b.action {
+				actions[k] = b.action
+				continue
+			}
+			action := b.action
+			if _, remapped := overrides[other]; remapped {
+				action, other = other, action
+			}
+			return nil, fmt.Errorf("%s: key %q is already bound to %s", action, keyLabel(k), other)
+		}
+	}
+	return actions, nil
+}
+
+func defaultKeyMap() *KeyMap {
+	km, _ := ParseKeys(nil)
+	return km
+}
+
+// splitKeys parses "j, ctrl+n" into key names as bubbletea reports them.
+func splitKeys(value string) []string {
+	var keys []string
+	for _, k := range strings.Split(value, ",") {
+		k = strings.TrimSpace(k)
+		switch k {
+		case "":
+			continue
+		case "space":
+			k = " "
+		case "escape":
+			k = "esc"
+		case "return":
+			k = "enter"
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// resolve maps a pressed key to the action it triggers; ok is false when no
+// action is bound to the key.
+func (km *KeyMap) resolve(key string) (string, bool) {
+	action, ok := km.actions[key]
+	return action, ok
+}
+
+// label is the first key bound to action, for hints in the footer.
+func (km *KeyMap) label(action string) string {
+	for _, b := range km.bindings {
+		if b.action == action {
+			return keyLabel(b.keys[0])
+		}
+	}
+	return ""
+}
+
+func keyLabel(key string) string {
+	if key == " " {
+		return "space"
+	}
+	return key
+}
+
+// keyTypes are the named default keys; other keys are runes.
+var keyTypes = map[string]tea.KeyType{
+	"enter":  tea.KeyEnter,
+	"esc":    tea.KeyEsc,
+	"tab":    tea.KeyTab,
+	" ":      tea.KeySpace,
+	"up":     tea.KeyUp,
+	"down":   tea.KeyDown,
+	"pgup":   tea.KeyPgUp,
+	"pgdown": tea.KeyPgDown,
+	"home":   tea.KeyHome,
+	"end":    tea.KeyEnd,
+}
+
+// actionMsg is the message for the default key of action, so viewports see
+// the key their own keymap knows rather than the remapped one.
+func actionMsg(action string) tea.KeyMsg {
+	key := ""
+	for _, b := range defaultBindings {
+		if b.action == action {
+			key = b.keys[0]
+		}
+	}
+	if t, ok := keyTypes[key]; ok {
+		return tea.KeyMsg{Type: t}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// renderHelp fills the help overlay with the active bindings.
+func (m *Model) renderHelp() {
+	label := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	lines := []string{"Key bindings (remap under tui.keys in services.yaml)", ""}
+	for _, b := range m.keys.bindings {
+		if b.action == "tab-postgres" && m.postgresURL == "" {
+			continue
+		}
+		if b.action == "watch" && !m.watching {
+			continue
+		}
+		keys := make([]string, len(b.keys))
+		for i, k := range b.keys {
+			keys[i] = keyLabel(k)
+		}
+		lines = append(lines, fmt.Sprintf("%-14s %s %s", strings.Join(keys, ", "), label.Render(fmt.Sprintf("%-15s", b.action)), b.help))
+	}
+	m.helpViewport.SetContent(strings.Join(lines, "\n"))
+}
+
+func (m *Model) toggleHelp() {
+	m.showHelp = !m.showHelp
+	if m.showHelp {
+		m.renderHelp()
+		m.helpViewport.GotoTop()
+	}
+}
+
+// helpView is the overlay shown in place of the left panel content.
+func (m *Model) helpView() string {
+	title := lipgloss.NewStyle().Foreground(m.theme.Dim).Render("Help ─ " + m.keys.label("help") + "/" + m.keys.label("close") + " close • " + m.keys.label("down") + "/" + m.keys.label("up") + " scroll")
+	return lipgloss.JoinVertical(lipgloss.Left, title, m.helpViewport.View())
+}
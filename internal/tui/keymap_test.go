@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func Test_ParseKeys(t *testing.T) {
+	km, err := ParseKeys(map[string]string{"quit": "ctrl+q", "down": "ctrl+n, j", "next-match": "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]struct {
+		action string
+		ok     bool
+	}{
+		"ctrl+q": {"quit", true},
+		"q":      {"", false},
+		"ctrl+n": {"down", true},
+		"j":      {"down", true},
+		"down":   {"", false},
+		"m":      {"next-match", true},
+		"n":      {"", false},
+		"h":      {"select-none", true},
+		"f":      {"follow", true},
+		"home":   {"top", true},
+	}
+	for pressed, want := range cases {
+		action, ok := km.resolve(pressed)
+		if action != want.action || ok != want.ok {
+			t.Errorf("resolve(%q) = %q, %v; want %q, %v", pressed, action, ok, want.action, want.ok)
+		}
+	}
+	if got := km.label("down"); got != "ctrl+n" {
+		t.Errorf("label(down) = %q", got)
+	}
+
+	for _, bad := range []map[string]string{
+		{"explode": "x"},
+		{"quit": "f"},
+		{"quit": "ctrl+q", "help": "ctrl+q"},
+		{"quit": " , "},
+	} {
+		if _, err := ParseKeys(bad); err == nil {
+			t.Errorf("ParseKeys(%v): want error", bad)
+		}
+	}
+	if _, err := ParseKeys(nil); err != nil {
+		t.Errorf("default bindings: %v", err)
+	}
+	shared := []keyBinding{{"select-none", []string{"n"}, ""}, {"next-match", []string{"n"}, ""}}
+	if _, err := keyActions(shared, nil); err == nil {
+		t.Error("keyActions: want error for actions sharing a key")
+	}
+}
+
+// A remapped action must run the action, not whatever else has its default
+// key: next-match once shared "n" with select-none.
+func Test_remappedActionDispatch(t *testing.T) {
+	rows := []ServiceRow{{Name: "api", Status: "running"}}
+	m := NewModel(nil, nil, rows, Options{Keys: map[string]string{"next-match": "m"}})
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	for i := 0; i < 3; i++ {
+		m.appendLog(LogLine{Service: "api", Text: fmt.Sprintf("hit %d", i)})
+	}
+	m.renderViewport()
+	m.startSearch("hit")
+	before := m.search.curSeq
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if m.search.curSeq == before {
+		t.Error("m should step to the next match")
+	}
+	m.focusStatus = true
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if !m.serviceShown("api") {
+		t.Error("m should not hide services")
+	}
+}
+
+func Test_remappedKeys(t *testing.T) {
+	rows := []ServiceRow{{Name: "api", Status: "running"}}
+	m := NewModel(nil, nil, rows, Options{Keys: map[string]string{"follow": "F", "help": "H"}, Colors: map[string]string{"api": "#ff8800"}})
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if !m.follow {
+		t.Error("f is unbound and should not toggle follow")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	if m.follow {
+		t.Error("F should toggle follow")
+	}
+	if string(m.colorFor("api")) != "#ff8800" {
+		t.Errorf("colorFor(api) = %q", m.colorFor("api"))
+	}
+	if !strings.Contains(stripANSI(m.View()), "F follow") {
+		t.Error("footer should show the remapped key")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	if !m.showHelp {
+		t.Fatal("H should open the help overlay")
+	}
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "F              follow") || strings.Contains(view, "tab-postgres") {
+		t.Errorf("help should list active bindings:\n%s", view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	if m.follow {
+		t.Error("keys other than close should not act while help is open")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showHelp {
+		t.Error("esc should close the help overlay")
+	}
+}
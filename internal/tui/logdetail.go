@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// toggleDetail expands the selected log line into the detail pane, or closes
+// the pane when it is open. The selected line is the one last clicked, else
+// the last JSON line in view, else the last line in view.
+func (m *Model) toggleDetail() {
+	if m.detail != nil {
+		m.closeDetail()
+		return
+	}
+	idx, ok := m.selectedLogIndex()
+	if !ok {
+		return
+	}
+	line := m.logs[idx]
+	m.detail = &line
+	m.resize()
+	m.renderDetail()
+	m.renderViewport()
+}
+
+func (m *Model) closeDetail() {
+	m.detail = nil
+	m.resize()
+	m.renderViewport()
+}
+
+func (m *Model) selectedLogIndex() (int, bool) {
+	if len(m.lineIndex) == 0 {
+		return 0, false
+	}
+	if !m.lastClickTime.IsZero() {
+		if idx, ok := m.logIndexAtRow(m.rowAtOffset(m.logSelStart)); ok {
+			return idx, true
+		}
+	}
+	start := m.viewport.YOffset
+	end := start + m.viewport.Height
+	if end > len(m.lineIndex) {
+		end = len(m.lineIndex)
+	}
+	for row := end - 1; row >= start && row >= 0; row-- {
+		if idx, ok := m.logIndexAtRow(row); ok && m.logs[idx].display != "" {
+			return idx, true
+		}
+	}
+	return m.logIndexAtRow(end - 1)
+}
+
+// rowAtOffset returns the rendered row containing byte offset off of
+// lastLogContent.
+func (m *Model) rowAtOffset(off int) int {
+	if off > len(m.lastLogContent) {
+		off = len(m.lastLogContent)
+	}
+	if off < 0 {
+		off = 0
+	}
+	return strings.Count(m.lastLogContent[:off], "\n")
+}
+
+func (m *Model) logIndexAtRow(row int) (int, bool) {
+	if row < 0 || row >= len(m.lineIndex) {
+		return 0, false
+	}
+	idx := m.lineIndex[row]
+	if idx < 0 || idx >= len(m.logs) {
+		return 0, false
+	}
+	return idx, true
+}
+
+// detailHeight is the number of rows the detail pane takes from the logs.
+func (m *Model) detailHeight(total int) int {
+	if m.detail == nil {
+		return 0
+	}
+	h := total * 2 / 5
+	if h < 5 {
+		h = 5
+	}
+	if h > total-3 {
+		h = total - 3
+	}
+	return h
+}
+
+func (m *Model) renderDetail() {
+	if m.detail == nil {
+		return
+	}
+	width := m.detailViewport.Width
+	if width < 20 {
+		width = 20
+	}
+	body := m.detail.Text
+	if m.detail.display != "" {
+		body = prettyJSON(stripANSI(m.detail.Text))
+	}
+	lines := []string{}
+	for _, seg := range strings.Split(body, "\n") {
+		lines = append(lines, wrapRunes(seg, width)...)
+	}
+	m.detailViewport.SetContent(strings.Join(lines, "\n"))
+	m.detailViewport.GotoTop()
+}
+
+// renderDetailPane draws the pane title and content under the logs.
+func (m *Model) renderDetailPane() string {
+	title := fmt.Sprintf("─ %s", m.detail.Service)
+	if m.detail.Level != LevelUnknown {
+		title += " · " + m.detail.Level.String()
+	}
+	title += " ─ e/esc close • j/k scroll"
+	titleStyle := lipgloss.NewStyle().Foreground(m.colorFor(m.detail.Service)).Bold(true)
+	return lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render(title), m.detailViewport.View())
+}
+
+// rowsText returns rendered rows from..to (inclusive) as plain text, with
+// JSON lines replaced by their raw text so copies keep the original line.
+func (m *Model) rowsText(from, to int) string {
+	rows := strings.Split(m.lastLogContent, "\n")
+	if from < 0 {
+		from = 0
+	}
+	if to >= len(rows) {
+		to = len(rows) - 1
+	}
+	out := []string{}
+	lastRaw := -1
+	for row := from; row <= to; row++ {
+		idx, ok := m.logIndexAtRow(row)
+		if ok && m.logs[idx].display != "" {
+			if idx != lastRaw {
+				line := m.logs[idx]
+				out = append(out, fmt.Sprintf("[%s] %s", line.Service, stripANSI(line.Text)))
+				lastRaw = idx
+			}
+			continue
+		}
+		out = append(out, stripANSI(rows[row]))
+	}
+	return strings.Join(out, "\n")
+}
+
+// selectionTouchesJSON reports whether rendered rows from..to include a JSON
+// line.
+func (m *Model) selectionTouchesJSON(from, to int) bool {
+	for row := from; row <= to; row++ {
+		if idx, ok := m.logIndexAtRow(row); ok && m.logs[idx].display != "" {
+			return true
+		}
+	}
+	return false
+}
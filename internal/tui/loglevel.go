@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Level is the severity detected in a log line. LevelUnknown sorts below
+// every real level.
+type Level int
+
+const (
+	LevelUnknown Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return ""
+}
+
+// levelFilterSteps is the order the level filter cycles through; LevelUnknown
+// means "show everything".
+var levelFilterSteps = []Level{LevelUnknown, LevelInfo, LevelWarn, LevelError}
+
+func nextLevelFilter(cur Level) Level {
+	for i, l := range levelFilterSteps {
+		if l == cur {
+			return levelFilterSteps[(i+1)%len(levelFilterSteps)]
+		}
+	}
+	return LevelUnknown
+}
+
+func levelFilterLabel(min Level) string {
+	switch min {
+	case LevelUnknown:
+		return "all"
+	case LevelError:
+		return "error"
+	}
+	return strings.ToLower(min.String()) + "+"
+}
+
+var (
+	// Leading timestamp: ISO-ish dates, optionally bracketed (Python logging,
+	// Celery, most Go/Node loggers).
+	logStampRe = regexp.MustCompile(`^\[?\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?\]?`)
+	// Upper-case level tokens near the start of the line: "ERROR:root:",
+	// "INFO:     127.0.0.1", "- WARNING -", "[...: ERROR/MainProcess]".
+	logLevelTokenRe = regexp.MustCompile(`\b(CRITICAL|FATAL|ERROR|ERR|WARNING|WARN|INFO|DEBUG|TRACE)\b`)
+	// key=value loggers (logfmt, structlog): level=error.
+	logLevelKVRe = regexp.MustCompile(`(?i)\b(?:level|lvl|severity)=["']?(\w+)`)
+	// Compiler/bundler style prefixes (Vite, bun, tsc): "error: ...",
+	// "✘ [ERROR] ...", "[vite] Internal server error".
+	logLevelPrefixRe = regexp.MustCompile(`(?i)^\s*(?:[✘✖×▲⚠]\s*)?(?:\[vite\].*?\b)?(error|warning|warn)\b`)
+	// Last line of a Python traceback: "ValueError: bad input".
+	logExceptionRe = regexp.MustCompile(`^[A-Za-z_][\w.]*(?:Error|Exception|Exit|Interrupt)(?::|$)`)
+)
+
+// levelTokenWindow is how far into a line level tokens are looked for, so
+// "ERROR" inside a request body does not mark the line.
+const levelTokenWindow = 80
+
+// detectLevel extracts the severity of a log line and the byte length of a
+// leading timestamp (0 when there is none). text must not contain ANSI codes.
+func detectLevel(text string) (Level, int) {
+	fields, _ := parseJSONLog(text)
+	return parsedLevel(text, fields)
+}
+
+// parsedLevel is detectLevel for a line already run through parseJSONLog;
+// fields is nil when the line is not a JSON object.
+func parsedLevel(text string, fields []jsonField) (Level, int) {
+	stampEnd := 0
+	if loc := logStampRe.FindStringIndex(text); loc != nil {
+		stampEnd = loc[1]
+	}
+	if fields != nil {
+		return jsonLevel(fields), stampEnd
+	}
+	trimmed := strings.TrimSpace(text)
+	if strings.HasPrefix(trimmed, "Traceback (most recent call last)") || logExceptionRe.MatchString(trimmed) {
+		return LevelError, stampEnd
+	}
+	head := text
+	if len(head) > levelTokenWindow {
+		head = head[:levelTokenWindow]
+	}
+	if m := logLevelKVRe.FindStringSubmatch(head); m != nil {
+		if l := parseLevelName(m[1]); l != LevelUnknown {
+			return l, stampEnd
+		}
+	}
+	if m := logLevelTokenRe.FindStringSubmatch(head); m != nil {
+		return parseLevelName(m[1]), stampEnd
+	}
+	if m := logLevelPrefixRe.FindStringSubmatch(text); m != nil {
+		return parseLevelName(m[1]), stampEnd
+	}
+	return LevelUnknown, stampEnd
+}
+
+// jsonLevel reads the level field of a parsed JSON log line.
+func jsonLevel(fields []jsonField) Level {
+	for _, key := range jsonLevelKeys {
+		for _, f := range fields {
+			if f.Key != key {
+				continue
+			}
+			var v any
+			_ = json.Unmarshal(f.Value, &v)
+			switch v := v.(type) {
+			case string:
+				return parseLevelName(v)
+			case float64:
+				// pino/bunyan numeric levels.
+				switch {
+				case v >= 50:
+					return LevelError
+				case v >= 40:
+					return LevelWarn
+				case v >= 30:
+					return LevelInfo
+				default:
+					return LevelDebug
+				}
+			}
+		}
+	}
+	return LevelUnknown
+}
+
+func parseLevelName(name string) Level {
+	switch strings.ToUpper(name) {
+	case "CRITICAL", "FATAL", "ERROR", "ERR", "PANIC", "ALERT", "EMERGENCY":
+		return LevelError
+	case "WARNING", "WARN":
+		return LevelWarn
+	case "INFO", "NOTICE":
+		return LevelInfo
+	case "DEBUG", "TRACE":
+		return LevelDebug
+	}
+	return LevelUnknown
+}
+
+// lineLevel returns the level of line, falling back to the previous level of
+// the same service for continuation lines (indented traceback frames, wrapped
+// messages) and to the pseudo-services floppy itself logs under. fields is
+// the line parsed by parseJSONLog, nil when it is not JSON.
+func lineLevel(line LogLine, fields []jsonField, prev Level) (Level, int) {
+	plain := stripANSI(line.Text)
+	level, stampEnd := parsedLevel(plain, fields)
+	if level != LevelUnknown {
+		return level, stampEnd
+	}
+	switch line.Service {
+	case "ERROR":
+		return LevelError, stampEnd
+	case "WARN":
+		return LevelWarn, stampEnd
+	}
+	if prev != LevelUnknown && plain != "" && (plain[0] == ' ' || plain[0] == '\t') {
+		return prev, stampEnd
+	}
+	return LevelUnknown, stampEnd
+}
+
+// levelStyle colors a whole line by severity; unknown and info lines keep
+// the service's own colors.
+func (t Theme) levelStyle(l Level) (lipgloss.Style, bool) {
+	switch l {
+	case LevelError:
+		return lipgloss.NewStyle().Foreground(t.Error), true
+	case LevelWarn:
+		return lipgloss.NewStyle().Foreground(t.Warn), true
+	case LevelDebug:
+		return lipgloss.NewStyle().Foreground(t.Muted), true
+	}
+	return lipgloss.Style{}, false
+}
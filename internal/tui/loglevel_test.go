@@ -0,0 +1,56 @@
+package tui
+
+import "testing"
+
+func Test_detectLevel(t *testing.T) {
+	tests := []struct {
+		text  string
+		level Level
+		stamp int
+	}{
+		{"2025-01-02 10:11:12,345 - app.db - WARNING - slow query", LevelWarn, 23},
+		{"ERROR:root:boom", LevelError, 0},
+		{`INFO:     127.0.0.1:5000 - "GET /error HTTP/1.1" 500`, LevelInfo, 0},
+		{"[2025-01-02 10:11:12,345: ERROR/MainProcess] Task failed", LevelError, 24},
+		{`{"level":"warn","msg":"retrying"}`, LevelWarn, 0},
+		{`{"level":50,"msg":"crash"}`, LevelError, 0},
+		{`{"level":null,"severity":"debug"}`, LevelDebug, 0},
+		{`{"msg":"ERROR in the request body"}`, LevelUnknown, 0},
+		{"time=2025-01-02T10:11:12Z level=debug msg=tick", LevelDebug, 0},
+		{"Traceback (most recent call last):", LevelError, 0},
+		{"ValueError: bad input", LevelError, 0},
+		{"error: Cannot find module 'x'", LevelError, 0},
+		{"[vite] Internal server error: Failed to resolve import", LevelError, 0},
+		{"  VITE v5.0.0  ready in 300 ms", LevelUnknown, 0},
+		{"2025-01-02T10:11:12.5Z started", LevelUnknown, 22},
+	}
+	for _, tt := range tests {
+		level, stamp := detectLevel(tt.text)
+		if level != tt.level || stamp != tt.stamp {
+			t.Errorf("detectLevel(%q) = %v, %d; want %v, %d", tt.text, level, stamp, tt.level, tt.stamp)
+		}
+	}
+}
+
+func Test_lineLevel_continuation(t *testing.T) {
+	if l, _ := lineLevel(LogLine{Service: "api", Text: `  File "app.py", line 3, in main`}, nil, LevelError); l != LevelError {
+		t.Errorf("indented frame after an error = %v, want ERROR", l)
+	}
+	if l, _ := lineLevel(LogLine{Service: "api", Text: "next request"}, nil, LevelError); l != LevelUnknown {
+		t.Errorf("unindented line = %v, want unknown", l)
+	}
+	if l, _ := lineLevel(LogLine{Service: "WARN", Text: "PTY not permitted"}, nil, LevelUnknown); l != LevelWarn {
+		t.Errorf("floppy WARN line = %v, want WARN", l)
+	}
+}
+
+func Test_nextLevelFilter(t *testing.T) {
+	l := LevelUnknown
+	want := []string{"info+", "warn+", "error", "all"}
+	for _, w := range want {
+		l = nextLevelFilter(l)
+		if got := levelFilterLabel(l); got != w {
+			t.Errorf("levelFilterLabel = %q, want %q", got, w)
+		}
+	}
+}
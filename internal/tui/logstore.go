@@ -0,0 +1,381 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultScrollback is the number of lines kept in memory per service.
+	DefaultScrollback = 2000
+	// loadOlderLines is how many spilled lines per service one scroll past
+	// the top brings back.
+	loadOlderLines = 500
+	// maxSpilledLines bounds the lines kept on disk per service; past it the
+	// oldest quarter is dropped from the spill file.
+	maxSpilledLines = 50000
+)
+
+// spillRecord is one log line as stored on disk.
+type spillRecord struct {
+	Seq   uint64    `json:"seq"`
+	Time  time.Time `json:"time"`
+	Text  string    `json:"text"`
+	Level Level     `json:"level"`
+}
+
+type spillEntry struct {
+	seq uint64
+	off int64
+	n   int
+}
+
+// logSpill keeps log lines evicted from memory in one JSONL file per
+// service. Each service's lines are evicted oldest first, so its entries are
+// in seq order and lines from loaded[svc] onwards are the ones currently
+// loaded back into the view.
+type logSpill struct {
+	parent  string
+	dir     string // created on first write, removed by Close
+	files   map[string]*os.File
+	size    map[string]int64
+	entries map[string][]spillEntry
+	loaded  map[string]int
+	err     error
+}
+
+func newLogSpill(parent string) *logSpill {
+	return &logSpill{
+		parent:  parent,
+		files:   map[string]*os.File{},
+		size:    map[string]int64{},
+		entries: map[string][]spillEntry{},
+		loaded:  map[string]int{},
+	}
+}
+
+// SpillFileName is the name of the spill file of service inside a session's
+// scrollback-* directory.
+func SpillFileName(service string) string {
+	return strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(service) + ".jsonl"
+}
+
+func (s *logSpill) file(service string) (*os.File, error) {
+	if f, ok := s.files[service]; ok {
+		return f, nil
+	}
+	if s.dir == "" {
+		if err := os.MkdirAll(s.parent, 0o755); err != nil {
+			return nil, err
+		}
+		dir, err := os.MkdirTemp(s.parent, "scrollback-")
+		if err != nil {
+			return nil, err
+		}
+		s.dir = dir
+	}
+	f, err := os.OpenFile(filepath.Join(s.dir, SpillFileName(service)), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	s.files[service] = f
+	return f, nil
+}
+
+// write appends evicted lines of one service. After the first error spilling
+// stops and evicted lines are dropped.
+func (s *logSpill) write(service string, lines []LogLine) {
+	if s.err != nil || len(lines) == 0 {
+		return
+	}
+	f, err := s.file(service)
+	if err != nil {
+		s.err = err
+		return
+	}
+	var buf []byte
+	entries := s.entries[service]
+	off := s.size[service]
+	for _, line := range lines {
+		data, _ := json.Marshal(spillRecord{Seq: line.seq, Time: line.Time, Text: line.Text, Level: line.Level})
+		data = append(data, '\n')
+		entries = append(entries, spillEntry{seq: line.seq, off: off + int64(len(buf)), n: len(data)})
+		buf = append(buf, data...)
+	}
+	if _, err := f.WriteAt(buf, off); err != nil {
+		s.err = err
+		return
+	}
+	s.size[service] = off + int64(len(buf))
+	if s.loaded[service] == len(s.entries[service]) {
+		s.loaded[service] = len(entries)
+	}
+	s.entries[service] = entries
+	if len(entries) > maxSpilledLines {
+		s.rotate(service, len(entries)-maxSpilledLines*3/4)
+	}
+}
+
+// rotate drops up to the oldest n spilled lines of service, moving the rest
+// to the start of its file. Lines loaded back into memory are kept.
+func (s *logSpill) rotate(service string, n int) {
+	if n > s.loaded[service] {
+		n = s.loaded[service]
+	}
+	if n <= 0 {
+		return
+	}
+	f := s.files[service]
+	entries := s.entries[service]
+	start := entries[n].off
+	rest := make([]byte, s.size[service]-start)
+	if _, err := f.ReadAt(rest, start); err != nil {
+		s.err = err
+		return
+	}
+	if _, err := f.WriteAt(rest, 0); err != nil {
+		s.err = err
+		return
+	}
+	if err := f.Truncate(int64(len(rest))); err != nil {
+		s.err = err
+		return
+	}
+	kept := make([]spillEntry, len(entries)-n)
+	for i, e := range entries[n:] {
+		e.off -= start
+		kept[i] = e
+	}
+	s.entries[service] = kept
+	s.size[service] = int64(len(rest))
+	s.loaded[service] -= n
+}
+
+// hasOlder reports whether service has spilled lines that are not loaded.
+func (s *logSpill) hasOlder(service string) bool {
+	return s.loaded[service] > 0
+}
+
+// older reads back up to n lines preceding the ones already loaded for
+// service, oldest first.
+func (s *logSpill) older(service string, n int) []LogLine {
+	end := s.loaded[service]
+	start := end - n
+	if start < 0 {
+		start = 0
+	}
+	f := s.files[service]
+	if f == nil || start == end {
+		return nil
+	}
+	out := make([]LogLine, 0, end-start)
+	for _, e := range s.entries[service][start:end] {
+		buf := make([]byte, e.n)
+		if _, err := f.ReadAt(buf, e.off); err != nil {
+			continue
+		}
+		var rec spillRecord
+		if err := json.Unmarshal(buf, &rec); err != nil {
+			continue
+		}
+		out = append(out, LogLine{Service: service, Text: rec.Text, Level: rec.Level, Time: rec.Time, seq: rec.Seq, fromDisk: true})
+	}
+	s.loaded[service] = start
+	return out
+}
+
+// unload marks every spilled line as on disk only again.
+func (s *logSpill) unload() {
+	for service, entries := range s.entries {
+		s.loaded[service] = len(entries)
+	}
+}
+
+// Close removes the spill files.
+func (s *logSpill) Close() error {
+	for _, f := range s.files {
+		f.Close()
+	}
+	s.files = map[string]*os.File{}
+	if s.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(s.dir)
+}
+
+// trimScrollback evicts the oldest in-memory lines of service beyond the
+// scrollback limit, spilling them to disk. It runs once a service is 10% over
+// its limit so eviction is a single pass over the buffer.
+func (m *Model) trimScrollback(service string) {
+	slack := m.scrollback / 10
+	if slack < 1 {
+		slack = 1
+	}
+	if m.logCounts[service] <= m.scrollback+slack {
+		return
+	}
+	drop := m.logCounts[service] - m.scrollback
+	// While older lines are loaded back, evicted lines stay in view as loaded
+	// lines so there is no gap between them and the in-memory window.
+	keep := m.spill != nil && m.loadedLines > 0 && m.spill.loaded[service] < len(m.spill.entries[service])
+	evicted := make([]LogLine, 0, drop)
+	kept := m.logs[:0]
+	for _, line := range m.logs {
+		if drop > 0 && line.Service == service && !line.fromDisk {
+			drop--
+			evicted = append(evicted, line)
+			if keep {
+				line.fromDisk = true
+				m.loadedLines++
+				kept = append(kept, line)
+			}
+			continue
+		}
+		kept = append(kept, line)
+	}
+	m.logs = kept
+	m.logCounts[service] = m.scrollback
+	if m.spill != nil {
+		m.spill.write(service, evicted)
+		if keep && m.spill.err == nil {
+			m.unloadOldest(service, len(evicted))
+		}
+	}
+}
+
+// unloadOldest drops the n oldest loaded lines of service from memory, so
+// lines kept in view while scrolled back do not grow the loaded window.
+func (m *Model) unloadOldest(service string, n int) {
+	dropped := map[int]bool{}
+	kept := m.logs[:0]
+	for i, line := range m.logs {
+		if len(dropped) < n && line.fromDisk && line.Service == service {
+			dropped[i] = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	m.logs = kept
+	m.loadedLines -= len(dropped)
+	m.spill.loaded[service] += len(dropped)
+	// Keep the view on the same line.
+	rowsAbove := 0
+	for row, idx := range m.lineIndex {
+		if row >= m.viewport.YOffset {
+			break
+		}
+		if dropped[idx] {
+			rowsAbove++
+		}
+	}
+	if rowsAbove > 0 {
+		m.viewport.SetYOffset(m.viewport.YOffset - rowsAbove)
+	}
+}
+
+// loadOlder brings back spilled lines of the services in view when scrolling
+// past the top of the in-memory window. The line at the top stays in place.
+func (m *Model) loadOlder() bool {
+	if m.spill == nil || !m.viewport.AtTop() {
+		return false
+	}
+	var topSeq uint64
+	if idx, ok := m.logIndexAtRow(m.viewport.YOffset); ok {
+		topSeq = m.logs[idx].seq
+	}
+	if m.loadSpilled() == 0 {
+		return false
+	}
+	m.follow = false
+	m.renderViewport()
+	for row, idx := range m.lineIndex {
+		if m.logs[idx].seq == topSeq {
+			m.viewport.SetYOffset(row)
+			break
+		}
+	}
+	return true
+}
+
+// loadSpilled merges the next batch of spilled lines of the services in view
+// into the buffer and returns how many were loaded.
+func (m *Model) loadSpilled() int {
+	if m.spill == nil {
+		return 0
+	}
+	var loaded []LogLine
+	for service := range m.logCounts {
+		if !m.serviceShown(service) || !m.spill.hasOlder(service) {
+			continue
+		}
+		for _, line := range m.spill.older(service, loadOlderLines) {
+			line.stampEnd, line.display = lineLayout(line)
+			loaded = append(loaded, line)
+		}
+	}
+	if len(loaded) > 0 {
+		m.logs = mergeBySeq(m.logs, sortBySeq(loaded))
+		m.loadedLines += len(loaded)
+	}
+	return len(loaded)
+}
+
+// dropLoaded forgets lines loaded back from disk once the view follows the
+// tail again.
+func (m *Model) dropLoaded() {
+	kept := m.logs[:0]
+	for _, line := range m.logs {
+		if !line.fromDisk {
+			kept = append(kept, line)
+		}
+	}
+	m.logs = kept
+	m.loadedLines = 0
+	m.spill.unload()
+}
+
+func (m *Model) serviceShown(service string) bool {
+	if len(m.filters) == 0 {
+		return true
+	}
+	return m.filters[service]
+}
+
+// levelShown reports whether line passes the level filter.
+func (m *Model) levelShown(line LogLine) bool {
+	return m.minLevel == LevelUnknown || line.Level >= m.minLevel
+}
+
+func sortBySeq(lines []LogLine) []LogLine {
+	// Lines come per service, each already in order; a merge per service
+	// keeps this linear in practice.
+	out := []LogLine{}
+	start := 0
+	for i := 1; i <= len(lines); i++ {
+		if i == len(lines) || lines[i].seq < lines[i-1].seq {
+			out = mergeBySeq(out, lines[start:i])
+			start = i
+		}
+	}
+	return out
+}
+
+// mergeBySeq merges two slices ordered by seq.
+func mergeBySeq(a, b []LogLine) []LogLine {
+	out := make([]LogLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i].seq <= b[j].seq {
+			out = append(out, a[i])
+			i++
+		} else {
+			out = append(out, b[j])
+			j++
+		}
+	}
+	out = append(out, a[i:]...)
+	return append(out, b[j:]...)
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func countService(m *Model, service string) (mem, disk int) {
+	for _, line := range m.logs {
+		if line.Service != service {
+			continue
+		}
+		if line.fromDisk {
+			disk++
+		} else {
+			mem++
+		}
+	}
+	return mem, disk
+}
+
+func Test_scrollback_perService(t *testing.T) {
+	m := NewModel(nil, nil, nil, Options{Scrollback: 10})
+	m.appendLog(LogLine{Service: "api", Text: "the api error"})
+	for i := 0; i < 500; i++ {
+		m.appendLog(LogLine{Service: "portal", Text: fmt.Sprintf("chatty %d", i)})
+	}
+	if mem, _ := countService(m, "api"); mem != 1 {
+		t.Errorf("api lines = %d, want 1: a chatty service must not evict others", mem)
+	}
+	if mem, _ := countService(m, "portal"); mem < 10 || mem > 11 {
+		t.Errorf("portal lines = %d, want about the scrollback of 10", mem)
+	}
+	if last := m.logs[len(m.logs)-1].Text; last != "chatty 499" {
+		t.Errorf("last line = %q", last)
+	}
+}
+
+func Test_scrollback_spillAndLoadOlder(t *testing.T) {
+	parent := t.TempDir()
+	m := NewModel(nil, nil, nil, Options{Scrollback: 10, SpillDir: parent})
+	m.viewport.Width = 120
+	m.viewport.Height = 5
+	for i := 0; i < 1200; i++ {
+		m.appendLog(LogLine{Service: "portal", Text: fmt.Sprintf("line %d", i)})
+	}
+	m.renderViewport()
+	first := m.logs[0].seq
+
+	m.viewport.GotoTop()
+	if !m.loadOlder() {
+		t.Fatal("loadOlder: want lines loaded from disk")
+	}
+	mem, disk := countService(m, "portal")
+	if disk != loadOlderLines {
+		t.Errorf("loaded %d lines, want %d", disk, loadOlderLines)
+	}
+	for i := 1; i < len(m.logs); i++ {
+		if m.logs[i].seq != m.logs[i-1].seq+1 {
+			t.Fatalf("gap between seq %d and %d", m.logs[i-1].seq, m.logs[i].seq)
+		}
+	}
+	if m.logs[0].seq != first-loadOlderLines || m.logs[0].Text != fmt.Sprintf("line %d", first-loadOlderLines-1) {
+		t.Errorf("oldest loaded = %d %q", m.logs[0].seq, m.logs[0].Text)
+	}
+	if idx := m.lineIndex[m.viewport.YOffset]; m.logs[idx].seq != first {
+		t.Errorf("view should stay on seq %d, at %d", first, m.logs[idx].seq)
+	}
+
+	// New lines while scrolled back keep the loaded history contiguous.
+	for i := 1200; i < 1220; i++ {
+		m.appendLog(LogLine{Service: "portal", Text: fmt.Sprintf("line %d", i)})
+	}
+	for i := 1; i < len(m.logs); i++ {
+		if m.logs[i].seq != m.logs[i-1].seq+1 {
+			t.Fatalf("gap between seq %d and %d after new lines", m.logs[i-1].seq, m.logs[i].seq)
+		}
+	}
+	if _, disk := countService(m, "portal"); disk != loadOlderLines {
+		t.Errorf("loaded window grew to %d lines while scrolled back, want %d", disk, loadOlderLines)
+	}
+
+	// Following the tail again drops what was loaded.
+	m.follow = true
+	m.renderViewport()
+	if mem2, disk2 := countService(m, "portal"); disk2 != 0 || mem2 > 11 {
+		t.Errorf("after follow: mem=%d disk=%d (before: mem=%d)", mem2, disk2, mem)
+	}
+	m.viewport.GotoTop()
+	if !m.loadOlder() {
+		t.Error("loadOlder after unload: want lines again")
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(parent); len(entries) != 0 {
+		t.Errorf("Close left %d entries in the spill dir", len(entries))
+	}
+}
+
+func Test_logSpill_rotate(t *testing.T) {
+	m := NewModel(nil, nil, nil, Options{Scrollback: 100, SpillDir: t.TempDir()})
+	defer m.Close()
+	for i := 0; i < maxSpilledLines+1000; i++ {
+		m.appendLog(LogLine{Service: "portal", Text: fmt.Sprintf("line %d", i)})
+	}
+	entries := m.spill.entries["portal"]
+	if len(entries) > maxSpilledLines {
+		t.Fatalf("spilled %d lines, want at most %d", len(entries), maxSpilledLines)
+	}
+	if info, _ := m.spill.files["portal"].Stat(); info.Size() != m.spill.size["portal"] {
+		t.Errorf("file size %d, tracked %d", info.Size(), m.spill.size["portal"])
+	}
+	if entries[0].off != 0 || entries[0].seq < 1000 {
+		t.Errorf("oldest spilled line: %+v, want the oldest lines rotated out", entries[0])
+	}
+	if m.loadSpilled() != loadOlderLines {
+		t.Fatal("loadSpilled: want lines after rotation")
+	}
+	for _, line := range m.logs {
+		if line.fromDisk && line.Text != fmt.Sprintf("line %d", line.seq-1) {
+			t.Fatalf("seq %d reads back as %q", line.seq, line.Text)
+		}
+	}
+}
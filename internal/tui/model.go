@@ -11,6 +11,7 @@ import (
 
 	"floppy-go/internal/dockerstats"
 	"floppy-go/internal/postgresstats"
+	"floppy-go/internal/procstats"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -21,95 +22,255 @@ import (
 type LogLine struct {
 	Service string
 	Text    string
+	// Level is detected from Text when the line is appended unless the
+	// sender already set it.
+	Level Level
+	// Time is when the line was read; the time it is appended when zero.
+	Time time.Time
+
+	stampEnd int              // byte length of a leading timestamp in the shown text, dimmed
+	display  string           // compact rendering of a JSON line; Text stays the raw line
+	seq      uint64           // arrival order, stable across buffer trimming
+	fromDisk bool             // loaded back from the scrollback spill
+	rows     [2]*renderedRows // last renderings (main view and split panes), see renderLogLines
+}
+
+// shown is the text rendered in the log viewport.
+func (l LogLine) shown() string {
+	if l.display != "" {
+		return l.display
+	}
+	return l.Text
 }
 
 type StatusUpdate struct {
-	Name   string
-	Status string
-	PID    int
+	Name     string
+	Status   string
+	PID      int
+	ExitCode *int // set when a task finishes
+}
+
+// Request asks the manager to act on services on behalf of the TUI.
+type Request struct {
+	Action  string // RequestWatch or RequestRestart
+	Service string
+	Enabled bool
+}
+
+const (
+	// RequestWatch turns automatic restarts on file changes on or off.
+	RequestWatch = "watch"
+	// RequestRestart restarts Service.
+	RequestRestart = "restart"
+)
+
+// Options configures optional TUI features.
+type Options struct {
+	PostgresURL   string
+	DockerEnabled bool
+	// Requests receives actions for the manager; nil disables them.
+	Requests chan<- Request
+	// Watching is true when at least one service restarts on file changes.
+	Watching bool
+	// Scrollback is the number of log lines kept in memory per service;
+	// 0 means DefaultScrollback.
+	Scrollback int
+	// SpillDir is where lines beyond the scrollback are written so they can
+	// be scrolled back to; empty drops them.
+	SpillDir string
+	// ExportDir is where x/X write log exports; the working directory when
+	// empty.
+	ExportDir string
+	// Describe returns the service detail panel contents (enter on a
+	// service row); nil disables the panel.
+	Describe func(service string) (ServiceInfo, bool)
+	// Keys remaps actions to keys, see ParseKeys.
+	Keys map[string]string
+	// Theme is "dark" (default) or "light".
+	Theme string
+	// Colors pins service name colors (ANSI numbers or #rrggbb) instead of
+	// picking them from the theme palette.
+	Colors map[string]string
 }
 
 type ServiceRow struct {
-	Name   string
-	Status string
-	Port   int
+	Name        string
+	Status      string
+	Port        int
+	PID         int
+	MemoryLimit int64 // bytes; 0 when the service has no memory limit
+	Task        bool  // one-shot task rather than a long-running service
+	ExitCode    *int
 }
 
 // Left panel tab indices (gh-dash style tabs)
 const (
-	TabAppLogs = 0
+	TabAppLogs  = 0
 	TabPostgres = 1
+	TabErrors   = 2
 )
 
 type Model struct {
-	viewport    viewport.Model
-	pgViewport  viewport.Model
-	activeTab   int // TabAppLogs or TabPostgres
-	logCh       <-chan LogLine
-	statusCh    <-chan StatusUpdate
-	logs        []LogLine
-	statuses    map[string]ServiceRow
-	filters     map[string]bool
-	colors      map[string]lipgloss.Color
-	width       int
-	height      int
-	interrupted bool
-	follow      bool
-	focusStatus bool
-	selected    int
-	filterMode  bool
-	filterText  string
-	mu          sync.Mutex
-	initialized bool
+	viewport     viewport.Model
+	pgViewport   viewport.Model
+	activeTab    int // TabAppLogs, TabPostgres or TabErrors
+	logCh        <-chan LogLine
+	statusCh     <-chan StatusUpdate
+	logs         []LogLine
+	statuses     map[string]ServiceRow
+	filters      map[string]bool
+	minLevel     Level            // hide lines below this level; LevelUnknown shows all
+	lastLevel    map[string]Level // per service, for continuation lines
+	errCounts    map[string]int   // ERROR lines seen per service
+	errorLog     *errorLog        // tracebacks and error lines grouped by type
+	errSelected  int
+	errViewport  viewport.Model
+	colors       map[string]lipgloss.Color
+	width        int
+	height       int
+	interrupted  bool
+	follow       bool
+	focusStatus  bool
+	selected     int
+	filterMode   bool
+	filterText   string
+	searchMode   bool
+	jumpMode     bool // typing a time to jump to (T)
+	jumpText     string
+	jumpErr      string
+	showTimes    bool // receive time before each line (t)
+	exportDir    string
+	notice       string // transient footer message, e.g. export result
+	describe     func(string) (ServiceInfo, bool)
+	serviceInfo  *ServiceInfo // open service detail panel (enter)
+	infoViewport viewport.Model
+	keys         *KeyMap
+	theme        Theme
+	showHelp     bool // key binding overlay (?)
+	paletteMode  bool // command palette (: or ctrl+p)
+	paletteText  string
+	paletteSel   int
+	helpViewport viewport.Model
+	noticeAt     time.Time
+	searchText   string
+	search       *logSearch
+	logSeq       uint64
+	scrollback   int            // in-memory lines per service
+	logCounts    map[string]int // in-memory lines per service, excluding fromDisk
+	spill        *logSpill      // nil when spilling is disabled
+	loadedLines  int            // fromDisk lines currently in logs
+	viewDirty    bool           // a message since the last render may have changed the log view
+	renderedSeq  uint64         // logSeq at the last render
+	mu           sync.Mutex
+	initialized  bool
 
 	// Postgres monitor (optional)
 	postgresURL string
 	pgStatsCh   chan postgresstats.Stats
 	pgStats     *postgresstats.Stats
+	pgSelected  int       // row in StuckQueries
+	pgConfirm   *pgAction // cancel/terminate awaiting y/n
 
 	// Docker monitor (optional)
 	dockerEnabled bool
 	dockerStatsCh chan dockerstats.Stats
 	dockerStats   *dockerstats.Stats
 
+	// Per-service process stats (CPU/RSS/fds/threads of each process group)
+	procSampler *procstats.Sampler
+	procStatsCh chan procSnapshot
+	procStats   map[string]procstats.Usage
+	procHistory map[string]*procHistory
+	procErr     string
+
+	requests    chan<- Request
+	watching    bool
+	watchPaused bool
+
 	tickCount int
 
+	// Split view: pinned services tiled in panes (p pins, v toggles)
+	panes      []*logPane
+	split      bool
+	activePane int
+
+	// Expanded log line (e), shown below the logs
+	detail         *LogLine
+	detailViewport viewport.Model
+
 	// Log selection (when focus is on logs)
-	lastLogContent  string
-	logSelStart     int
-	logSelEnd       int
-	logSelecting    bool
-	lastClickTime   time.Time
-	lastClickX      int
-	lastClickY      int
+	lastLogContent string
+	lineIndex      []int // rendered row -> index in logs
+	logSelStart    int
+	logSelEnd      int
+	logSelecting   bool
+	lastClickTime  time.Time
+	lastClickX     int
+	lastClickY     int
 }
 
 type tickMsg time.Time
 
-func NewModel(logCh <-chan LogLine, statusCh <-chan StatusUpdate, initial []ServiceRow, postgresURL string, dockerEnabled bool) *Model {
+func NewModel(logCh <-chan LogLine, statusCh <-chan StatusUpdate, initial []ServiceRow, opts Options) *Model {
 	statuses := map[string]ServiceRow{}
 	for _, row := range initial {
 		statuses[row.Name] = row
 	}
 
 	m := &Model{
-		viewport:     viewport.New(10, 10),
-		pgViewport:   viewport.New(10, 10),
-		activeTab:    TabAppLogs,
-		logCh:        logCh,
-		statusCh:     statusCh,
-		logs:         []LogLine{},
-		statuses:     statuses,
-		filters:      map[string]bool{},
-		colors:       map[string]lipgloss.Color{},
-		follow:       true,
-		postgresURL:  postgresURL,
-		dockerEnabled: dockerEnabled,
-	}
-	if postgresURL != "" {
+		viewport:       viewport.New(10, 10),
+		pgViewport:     viewport.New(10, 10),
+		errViewport:    viewport.New(10, 10),
+		infoViewport:   viewport.New(10, 10),
+		helpViewport:   viewport.New(10, 10),
+		detailViewport: viewport.New(10, 5),
+		activeTab:      TabAppLogs,
+		logCh:          logCh,
+		statusCh:       statusCh,
+		logs:           []LogLine{},
+		statuses:       statuses,
+		filters:        map[string]bool{},
+		lastLevel:      map[string]Level{},
+		errCounts:      map[string]int{},
+		errorLog:       newErrorLog(),
+		scrollback:     opts.Scrollback,
+		exportDir:      opts.ExportDir,
+		describe:       opts.Describe,
+		logCounts:      map[string]int{},
+		colors:         map[string]lipgloss.Color{},
+		follow:         true,
+		postgresURL:    opts.PostgresURL,
+		dockerEnabled:  opts.DockerEnabled,
+		requests:       opts.Requests,
+		watching:       opts.Watching,
+		procSampler:    procstats.NewSampler(),
+		procStatsCh:    make(chan procSnapshot, 1),
+		procStats:      map[string]procstats.Usage{},
+		procHistory:    map[string]*procHistory{},
+	}
+	if m.scrollback <= 0 {
+		m.scrollback = DefaultScrollback
+	}
+	m.theme = darkTheme
+	if t, err := ThemeByName(opts.Theme); err == nil {
+		m.theme = t
+	}
+	for name, color := range opts.Colors {
+		m.colors[name] = lipgloss.Color(color)
+	}
+	keys, err := ParseKeys(opts.Keys)
+	if err != nil {
+		keys = defaultKeyMap()
+		m.setNotice("keys: " + err.Error())
+	}
+	m.keys = keys
+	if opts.SpillDir != "" {
+		m.spill = newLogSpill(opts.SpillDir)
+	}
+	if m.postgresURL != "" {
 		m.pgStatsCh = make(chan postgresstats.Stats, 1)
 	}
-	if dockerEnabled {
+	if m.dockerEnabled {
 		m.dockerStatsCh = make(chan dockerstats.Stats, 1)
 	}
 	return m
@@ -124,6 +285,9 @@ func (m *Model) Init() tea.Cmd {
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tickMsg); !ok {
+		m.viewDirty = true
+	}
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -132,152 +296,112 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.renderViewport() // re-render with new dimensions so truncation and follow stay correct
 		return m, nil
 	case tea.KeyMsg:
-		if m.filterMode {
+		if m.jumpMode {
 			switch msg.String() {
 			case "esc":
-				m.filterMode = false
-				m.filterText = ""
+				m.jumpMode = false
+				m.jumpText = ""
 				return m, nil
 			case "enter":
-				m.filterMode = false
+				m.jumpMode = false
+				m.applyJump(m.jumpText)
+				m.jumpText = ""
 				return m, nil
 			case "backspace", "ctrl+h":
-				if len(m.filterText) > 0 {
-					m.filterText = m.filterText[:len(m.filterText)-1]
+				if len(m.jumpText) > 0 {
+					m.jumpText = m.jumpText[:len(m.jumpText)-1]
 				}
 				return m, nil
 			default:
 				if msg.Type == tea.KeyRunes {
-					m.filterText += msg.String()
+					m.jumpText += msg.String()
 				}
 				return m, nil
 			}
 		}
-		switch msg.String() {
-		case "ctrl+c":
-			if !m.focusStatus && m.activeTab == TabAppLogs && m.copyLogSelection() {
-				return m, nil
-			}
-			m.interrupted = true
-			return m, tea.Quit
-		case "q":
-			m.interrupted = true
-			return m, tea.Quit
-		case "1":
-			if m.postgresURL != "" {
-				m.activeTab = TabAppLogs
-			}
-			return m, nil
-		case "2":
-			if m.postgresURL != "" {
-				m.activeTab = TabPostgres
-			}
-			return m, nil
-		case "tab":
-			m.focusStatus = !m.focusStatus
-			return m, nil
-		case "/":
-			m.focusStatus = true
-			m.filterMode = true
-			return m, nil
-		case " ":
-			if m.focusStatus {
-				m.toggleSelectedFilter()
+		if m.searchMode {
+			switch msg.String() {
+			case "esc":
+				m.searchMode = false
+				m.searchText = ""
 				return m, nil
-			}
-		case "a":
-			if m.focusStatus {
-				m.setAllFilters(true)
+			case "enter":
+				m.searchMode = false
+				m.startSearch(m.searchText)
 				return m, nil
-			}
-		case "n":
-			if m.focusStatus {
-				m.setAllFilters(false)
+			case "backspace", "ctrl+h":
+				if len(m.searchText) > 0 {
+					m.searchText = m.searchText[:len(m.searchText)-1]
+				}
 				return m, nil
-			}
-		case "f":
-			if !m.focusStatus {
-				m.follow = !m.follow
-				if m.follow {
-					m.viewport.GotoBottom()
+			default:
+				if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+					m.searchText += msg.String()
 				}
 				return m, nil
 			}
-		case "Y":
-			if !m.focusStatus && m.copyVisibleLogs() {
+		}
+		if m.pgConfirm != nil {
+			return m, m.pgConfirmKey(msg)
+		}
+		if m.paletteMode {
+			return m, m.paletteKey(msg)
+		}
+		if m.filterMode {
+			switch msg.String() {
+			case "esc":
+				m.filterMode = false
+				m.filterText = ""
 				return m, nil
-			}
-		case "j", "down":
-			if m.focusStatus {
-				m.moveSelection(1)
+			case "enter":
+				m.filterMode = false
 				return m, nil
-			}
-			var cmd tea.Cmd
-			if m.activeTab == TabPostgres {
-				m.pgViewport, cmd = m.pgViewport.Update(msg)
-			} else {
-				m.viewport, cmd = m.viewport.Update(msg)
-				m.follow = m.viewport.AtBottom()
-			}
-			return m, cmd
-		case "k", "up":
-			if m.focusStatus {
-				m.moveSelection(-1)
+			case "backspace", "ctrl+h":
+				if len(m.filterText) > 0 {
+					m.filterText = m.filterText[:len(m.filterText)-1]
+				}
 				return m, nil
-			}
-			var cmd tea.Cmd
-			if m.activeTab == TabPostgres {
-				m.pgViewport, cmd = m.pgViewport.Update(msg)
-			} else {
-				m.viewport, cmd = m.viewport.Update(msg)
-				m.follow = m.viewport.AtBottom()
-			}
-			return m, cmd
-		case "g":
-			if m.focusStatus {
-				m.selected = 0
+			default:
+				if msg.Type == tea.KeyRunes {
+					m.filterText += msg.String()
+				}
 				return m, nil
 			}
-			if m.activeTab == TabPostgres {
-				m.pgViewport.GotoTop()
-			} else {
-				m.viewport.GotoTop()
-				m.follow = false
+		}
+		if msg.String() == "ctrl+c" {
+			if !m.focusStatus && m.activeTab == TabAppLogs && m.copyLogSelection() {
+				return m, nil
 			}
+			m.interrupted = true
+			return m, tea.Quit
+		}
+		action, ok := m.keys.resolve(msg.String())
+		if !ok {
 			return m, nil
-		case "G", "end":
-			if m.focusStatus {
-				m.selected = m.maxSelection()
+		}
+		if m.showHelp && action != "quit" {
+			if action == "help" || action == "close" {
+				m.showHelp = false
 				return m, nil
 			}
-			if m.activeTab == TabPostgres {
-				m.pgViewport.GotoBottom()
-			} else {
-				m.viewport.GotoBottom()
-				m.follow = true
-			}
-			return m, nil
-		case "pgup", "pgdown", "home":
 			var cmd tea.Cmd
-			if m.activeTab == TabPostgres {
-				m.pgViewport, cmd = m.pgViewport.Update(msg)
-			} else {
-				m.viewport, cmd = m.viewport.Update(msg)
-				m.follow = m.viewport.AtBottom()
-			}
+			m.helpViewport, cmd = m.helpViewport.Update(actionMsg(action))
 			return m, cmd
 		}
+		if c := m.boundCommand(action); c != nil {
+			return m, c.run(m)
+		}
+		return m, nil
 	case tea.MouseMsg:
 		if m.focusStatus {
 			return m, nil
 		}
 		// Log panel content area: left panel has border (1) + padding (1) = col 2.
-		// Row offset: top border (1) + tab bar row (1, always present) + blank separator (1) = 3.
-		// Without postgres the tab bar is not rendered: top border (1) = 1.
+		// Row offset: top border (1) + tab bar row (1) + blank separator (1) = 3.
 		contentLeft := 2
-		contentTop := 1
-		if m.postgresURL != "" {
-			contentTop = 3
+		contentTop := 3
+		if m.split && m.activeTab == TabAppLogs {
+			return m, m.paneMouse(msg, msg.X-contentLeft, msg.Y-contentTop)
 		}
 		inLogContent := m.activeTab == TabAppLogs && msg.X >= contentLeft && msg.Y >= contentTop &&
 			msg.X < contentLeft+m.viewport.Width && msg.Y < contentTop+m.viewport.Height
@@ -333,24 +457,45 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if msg.Button == tea.MouseButtonWheelUp || msg.Button == tea.MouseButtonWheelDown {
 			var cmd tea.Cmd
-			if m.activeTab == TabPostgres {
+			if m.activeTab == TabErrors {
+				m.errViewport, cmd = m.errViewport.Update(msg)
+			} else if m.activeTab == TabPostgres {
 				m.pgViewport, cmd = m.pgViewport.Update(msg)
 			} else {
-				m.viewport, cmd = m.viewport.Update(msg)
-				m.follow = m.viewport.AtBottom()
+				cmd = m.scrollLogs(msg, msg.Button == tea.MouseButtonWheelUp)
 			}
 			return m, cmd
 		}
+	case noticeMsg:
+		m.setNotice(string(msg))
+		return m, nil
+	case serviceInfoMsg:
+		m.setServiceInfo(msg)
+		return m, nil
 	case tickMsg:
 		m.drainLogs()
 		m.drainStatuses()
 		m.drainPgStats()
 		m.drainDockerStats()
+		m.drainProcStats()
 		m.tickCount++
+		var infoCmd tea.Cmd
+		if m.tickCount%10 == 0 {
+			infoCmd = m.refreshServiceInfo() // uptime, exit code
+		}
+		if m.notice != "" && time.Since(m.noticeAt) > noticeTTL {
+			m.notice = ""
+		}
 		if m.tickCount%30 == 1 {
 			if m.postgresURL != "" {
+				names := make([]string, 0, len(m.statuses))
+				for name := range m.statuses {
+					names = append(names, name)
+				}
+				pids := m.runningPIDs()
 				go func() {
 					s := postgresstats.Fetch(context.Background(), m.postgresURL)
+					s.Services = pgServiceSessions(s.Sessions, names, pids)
 					select {
 					case m.pgStatsCh <- s:
 					default:
@@ -366,9 +511,20 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}()
 			}
+			if pids := m.runningPIDs(); len(pids) > 0 || len(m.procStats) > 0 {
+				go func() {
+					s := fetchProcStats(m.procSampler, pids)
+					select {
+					case m.procStatsCh <- s:
+					default:
+					}
+				}()
+			}
 		}
-		m.renderViewport()
-		return m, tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg { return tickMsg(t) })
+		if m.viewDirty || m.logSeq != m.renderedSeq {
+			m.renderViewport()
+		}
+		return m, tea.Batch(infoCmd, tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg { return tickMsg(t) }))
 	}
 	return m, nil
 }
@@ -381,10 +537,29 @@ func (m *Model) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, body, footer)
 }
 
+// Close removes the scrollback spill files.
+func (m *Model) Close() error {
+	if m.spill == nil {
+		return nil
+	}
+	return m.spill.Close()
+}
+
 func (m *Model) Interrupted() bool {
 	return m.interrupted
 }
 
+// sendRequest forwards req to the manager without blocking the UI.
+func (m *Model) sendRequest(req Request) {
+	if m.requests == nil {
+		return
+	}
+	select {
+	case m.requests <- req:
+	default:
+	}
+}
+
 func (m *Model) drainLogs() {
 	for {
 		select {
@@ -405,6 +580,12 @@ func (m *Model) drainStatuses() {
 			if update.Status != "" {
 				row.Status = update.Status
 			}
+			if update.PID > 0 {
+				row.PID = update.PID
+			}
+			if update.ExitCode != nil {
+				row.ExitCode = update.ExitCode
+			}
 			m.statuses[update.Name] = row
 			if _, ok := m.filters[update.Name]; !ok {
 				m.filters[update.Name] = true
@@ -422,7 +603,7 @@ func (m *Model) drainPgStats() {
 	for {
 		select {
 		case s := <-m.pgStatsCh:
-			m.pgStats = &s
+			m.setPgStats(s)
 		default:
 			return
 		}
@@ -448,33 +629,220 @@ func (m *Model) appendLog(line LogLine) {
 	if service == "" {
 		service = "INFO"
 	}
-	m.logs = append(m.logs, LogLine{Service: service, Text: line.Text})
-	if len(m.logs) > 2000 {
-		m.logs = m.logs[len(m.logs)-2000:]
-	}
+	fields, _ := parseJSONLog(stripANSI(line.Text))
+	level, stampEnd := line.Level, 0
+	if level == LevelUnknown {
+		level, stampEnd = lineLevel(line, fields, m.lastLevel[service])
+	}
+	m.lastLevel[service] = level
+	if level == LevelError {
+		m.errCounts[service]++
+	}
+	display := ""
+	if fields != nil {
+		display, stampEnd = compactJSONLog(fields)
+	}
+	at := line.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	m.logSeq++
+	stored := LogLine{Service: service, Text: line.Text, Level: level, Time: at, stampEnd: stampEnd, display: display, seq: m.logSeq}
+	m.errorLog.observe(stored)
+	m.logs = append(m.logs, stored)
+	m.logCounts[service]++
+	m.trimScrollback(service)
 	if _, ok := m.filters[service]; !ok {
 		m.filters[service] = true
 	}
 }
 
+// scrollLogs passes a scroll key or wheel event to the log viewport. Scrolling
+// up while already at the top loads older lines from the scrollback spill.
+// moveCursor handles down (delta 1) and up (delta -1): it moves the
+// selection of the focused list, or scrolls the panel in view.
+func (m *Model) moveCursor(delta int) tea.Cmd {
+	action := "down"
+	if delta < 0 {
+		action = "up"
+	}
+	if m.focusStatus {
+		m.moveSelection(delta)
+		return m.refreshServiceInfo()
+	}
+	if m.activeTab == TabErrors {
+		m.moveErrorSelection(delta)
+		return nil
+	}
+	if m.activeTab == TabPostgres && len(m.stuckQueries()) > 0 {
+		m.movePgSelection(delta)
+		return nil
+	}
+	var cmd tea.Cmd
+	if m.detail != nil && m.activeTab == TabAppLogs {
+		m.detailViewport, cmd = m.detailViewport.Update(actionMsg(action))
+		return cmd
+	}
+	if m.activeTab == TabPostgres {
+		m.pgViewport, cmd = m.pgViewport.Update(actionMsg(action))
+		return cmd
+	}
+	return m.scrollLogs(actionMsg(action), delta < 0)
+}
+
+// gotoEdge handles top and bottom for the focused list or the panel in view.
+func (m *Model) gotoEdge(top bool) tea.Cmd {
+	if m.focusStatus {
+		if top {
+			m.selected = 0
+		} else {
+			m.selected = m.maxSelection()
+		}
+		return nil
+	}
+	switch {
+	case m.activeTab == TabErrors && top:
+		m.errSelected = 0
+	case m.activeTab == TabErrors:
+		m.errSelected = len(m.errorLog.groups) - 1
+	case m.activeTab == TabPostgres && top:
+		m.pgSelected = 0
+		m.pgViewport.GotoTop()
+	case m.activeTab == TabPostgres:
+		m.pgSelected = len(m.stuckQueries()) - 1
+		m.pgViewport.GotoBottom()
+	case top:
+		if m.viewport.AtTop() {
+			m.loadOlder()
+		}
+		m.viewport.GotoTop()
+		m.follow = false
+	default:
+		m.viewport.GotoBottom()
+		m.follow = true
+	}
+	return nil
+}
+
+// page handles page-up and page-down for the panel in view.
+func (m *Model) page(up bool) tea.Cmd {
+	msg := actionMsg("page-down")
+	if up {
+		msg = actionMsg("page-up")
+	}
+	var cmd tea.Cmd
+	if m.serviceInfo != nil {
+		m.infoViewport, cmd = m.infoViewport.Update(msg)
+	} else if m.activeTab == TabErrors {
+		m.errViewport, cmd = m.errViewport.Update(msg)
+	} else if m.activeTab == TabPostgres {
+		m.pgViewport, cmd = m.pgViewport.Update(msg)
+	} else {
+		cmd = m.scrollLogs(msg, up)
+	}
+	return cmd
+}
+
+func (m *Model) scrollLogs(msg tea.Msg, up bool) tea.Cmd {
+	atTop := m.viewport.AtTop()
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	m.follow = m.viewport.AtBottom()
+	if up && atTop {
+		m.loadOlder()
+	}
+	return cmd
+}
+
+// lineLayout recomputes the rendering hints of a line read back from disk.
+func lineLayout(line LogLine) (stampEnd int, display string) {
+	plain := stripANSI(line.Text)
+	if fields, ok := parseJSONLog(plain); ok {
+		display, stampEnd = compactJSONLog(fields)
+		return stampEnd, display
+	}
+	_, stampEnd = parsedLevel(plain, nil)
+	return stampEnd, ""
+}
+
 func (m *Model) renderViewport() {
 	contentWidth := m.viewport.Width
 	if contentWidth < 40 {
 		contentWidth = 80
 	}
 
+	if m.follow && m.loadedLines > 0 {
+		m.dropLoaded()
+	}
+	if m.search != nil {
+		m.search.resetMatches()
+	}
+	m.viewDirty, m.renderedSeq = false, m.logSeq
+	lines, index := m.renderLogLines(func(line LogLine) bool {
+		return m.serviceShown(line.Service)
+	}, contentWidth, m.search)
+	content := strings.Join(lines, "\n")
+	m.lastLogContent = content
+	m.lineIndex = index
+	if m.logSelStart != m.logSelEnd {
+		s, e := m.logSelStart, m.logSelEnd
+		if s > e {
+			s, e = e, s
+		}
+		if s < 0 {
+			s = 0
+		}
+		if e > len(content) {
+			e = len(content)
+		}
+		// Reverse video for selection (SGR 7)
+		content = content[:s] + "\x1b[7m" + content[s:e] + "\x1b[0m" + content[e:]
+	}
+	m.viewport.SetContent(content)
+	if m.follow {
+		m.viewport.GotoBottom()
+	}
+	if m.split {
+		m.renderPanes()
+	}
+}
+
+// renderLogLines wraps and styles the log lines include accepts (and the
+// level filter lets through) to contentWidth. It returns the rendered rows
+// and, per row, the index of its line in m.logs. Matches of search are
+// highlighted and recorded; pass nil to skip searching.
+func (m *Model) renderLogLines(include func(LogLine) bool, contentWidth int, search *logSearch) ([]string, []int) {
 	lines := make([]string, 0, len(m.logs))
-	showAll := len(m.filters) == 0
-	for _, line := range m.logs {
-		if !showAll {
-			if ok := m.filters[line.Service]; !ok {
-				continue
+	index := make([]int, 0, len(m.logs))
+	// Wrapping and styling dominate the cost of a render, so the rows of
+	// each line are kept from the previous render at the same width. Search
+	// highlights depend on the query, so searching bypasses them.
+	cached := search == nil || search.re == nil
+	key := rowCacheKey{width: contentWidth, times: m.showTimes}
+	for i, line := range m.logs {
+		if !include(line) {
+			continue
+		}
+		if !m.levelShown(line) {
+			continue
+		}
+		if rows, ok := line.cachedRows(key); ok && cached {
+			lines = append(lines, rows...)
+			for range rows {
+				index = append(index, i)
 			}
+			continue
 		}
+		start := len(lines)
 		color := m.colorFor(line.Service)
 		prefix := lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("[%s]", line.Service))
 		// Visible width of "[service] " (brackets + space, no ANSI)
 		prefixWidth := len(line.Service) + 3
+		if m.showTimes {
+			stamp := line.Time.Format("15:04:05.000")
+			prefix = lipgloss.NewStyle().Foreground(m.theme.Muted).Render(stamp) + " " + prefix
+			prefixWidth += len(stamp) + 1
+		}
 		textWidth := contentWidth - prefixWidth
 		if textWidth < 20 {
 			textWidth = 20
@@ -482,42 +850,52 @@ func (m *Model) renderViewport() {
 		indent := strings.Repeat(" ", prefixWidth)
 
 		// Split on embedded newlines first, then hard-wrap each segment.
+		text := search.matchText(line.shown())
 		first := true
-		for _, seg := range strings.Split(line.Text, "\n") {
+		nth := 0
+		for _, seg := range strings.Split(text, "\n") {
 			for _, chunk := range wrapRunes(seg, textWidth) {
+				locs, cur := search.locs(chunk, len(lines), line.seq, &nth)
+				chunk = m.theme.styleLogChunk(chunk, text, line, first, locs, cur)
 				if first {
 					lines = append(lines, fmt.Sprintf("%s %s", prefix, chunk))
 					first = false
 				} else {
 					lines = append(lines, indent+chunk)
 				}
+				index = append(index, i)
 			}
 		}
 		if first {
 			// Empty text
 			lines = append(lines, prefix)
+			index = append(index, i)
 		}
-	}
-	content := strings.Join(lines, "\n")
-	m.lastLogContent = content
-	if m.logSelStart != m.logSelEnd {
-		s, e := m.logSelStart, m.logSelEnd
-		if s > e {
-			s, e = e, s
-		}
-		if s < 0 {
-			s = 0
-		}
-		if e > len(content) {
-			e = len(content)
+		if cached {
+			m.logs[i].rows = [2]*renderedRows{{key: key, rows: append([]string(nil), lines[start:]...)}, line.rows[0]}
 		}
-		// Reverse video for selection (SGR 7)
-		content = content[:s] + "\x1b[7m" + content[s:e] + "\x1b[0m" + content[e:]
 	}
-	m.viewport.SetContent(content)
-	if m.follow {
-		m.viewport.GotoBottom()
+	return lines, index
+}
+
+// rowCacheKey is what rendered rows depend on besides the line itself.
+type rowCacheKey struct {
+	width int
+	times bool
+}
+
+type renderedRows struct {
+	key  rowCacheKey
+	rows []string
+}
+
+func (l LogLine) cachedRows(key rowCacheKey) ([]string, bool) {
+	for _, r := range l.rows {
+		if r != nil && r.key == key {
+			return r.rows, true
+		}
 	}
+	return nil, false
 }
 
 func (m *Model) resize() {
@@ -535,6 +913,19 @@ func (m *Model) resize() {
 	m.viewport.Height = h
 	m.pgViewport.Width = w
 	m.pgViewport.Height = h
+	m.errViewport.Width = w
+	m.errViewport.Height = h
+	m.infoViewport.Width = w
+	m.infoViewport.Height = h - 1 // title row
+	m.helpViewport.Width = w
+	m.helpViewport.Height = h - 1
+	m.layoutPanes()
+	if dh := m.detailHeight(h); dh > 0 {
+		m.viewport.Height = h - dh
+		m.detailViewport.Width = w
+		m.detailViewport.Height = dh - 1 // title row
+		m.renderDetail()
+	}
 }
 
 func (m *Model) renderTabBar() string {
@@ -546,50 +937,67 @@ func (m *Model) renderTabBar() string {
 	if m.postgresURL != "" && m.pgStats != nil && len(m.pgStats.StuckQueries) > 0 {
 		pgLabel = fmt.Sprintf(" Postgres (%d stuck) ", len(m.pgStats.StuckQueries))
 	}
-	inactive := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-	active := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("7"))
-	var app, pg string
-	if m.activeTab == TabAppLogs {
-		app = active.Render(appLabel)
-		pg = inactive.Render(pgLabel)
-	} else {
-		app = inactive.Render(appLabel)
-		pg = active.Render(pgLabel)
+	errLabel := " Errors "
+	if n := m.errorLog.total(); n > 0 {
+		errLabel = fmt.Sprintf(" Errors (%d) ", n)
 	}
-	return app + " " + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("|") + " " + pg
+	inactive := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	active := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Active)
+	tab := func(label string, idx int) string {
+		if m.activeTab == idx {
+			return active.Render(label)
+		}
+		return inactive.Render(label)
+	}
+	tabs := []string{tab(appLabel, TabAppLogs)}
+	if m.postgresURL != "" {
+		tabs = append(tabs, tab(pgLabel, TabPostgres))
+	}
+	tabs = append(tabs, tab(errLabel, TabErrors))
+	sep := " " + lipgloss.NewStyle().Foreground(m.theme.Border).Render("|") + " "
+	return strings.Join(tabs, sep)
 }
 
 func (m *Model) renderLeftPanel() string {
 	var content string
-	if m.postgresURL == "" {
-		// No Postgres: single panel (App Logs only)
-		content = m.viewport.View()
+	tabBar := m.renderTabBar()
+	switch {
+	case m.paletteMode:
+		content = m.paletteView()
+	case m.showHelp:
+		content = m.helpView()
+	case m.serviceInfo != nil:
+		content = m.serviceInfoView()
+	case m.activeTab == TabPostgres:
+		m.renderPostgresViewport()
+		content = m.pgViewport.View()
+	case m.activeTab == TabErrors:
+		m.renderErrorsViewport()
+		content = m.errViewport.View()
+	default:
+		content = m.logsView()
 		if !m.initialized && m.width > 0 {
 			m.initialized = true
 			m.renderViewport()
 		}
-		box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).Padding(0, 1)
-		return box.Width(m.viewport.Width + 2).Height(m.viewport.Height + 2).Render(content)
-	}
-	{
-		tabBar := m.renderTabBar()
-		if m.activeTab == TabAppLogs {
-			content = m.viewport.View()
-			if !m.initialized && m.width > 0 {
-				m.initialized = true
-				m.renderViewport()
-			}
-		} else {
-			m.renderPostgresViewport()
-			content = m.pgViewport.View()
-		}
-		box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).Padding(0, 1)
-		return box.Width(m.viewport.Width + 2).Height(m.viewport.Height + 3).Render(lipgloss.JoinVertical(lipgloss.Left, tabBar, content))
 	}
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(m.theme.Border).Padding(0, 1)
+	return box.Width(m.viewport.Width + 2).Height(m.pgViewport.Height + 3).Render(lipgloss.JoinVertical(lipgloss.Left, tabBar, content))
+}
+
+// logsView is the log viewport plus the detail pane when a line is expanded.
+func (m *Model) logsView() string {
+	if m.split {
+		return m.splitView()
+	}
+	if m.detail == nil {
+		return m.viewport.View()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), m.renderDetailPane())
 }
 
 func (m *Model) renderLogsPanel() string {
-	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).Padding(0, 1)
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(m.theme.Border).Padding(0, 1)
 	content := m.viewport.View()
 	if !m.initialized && m.width > 0 {
 		m.initialized = true
@@ -625,13 +1033,42 @@ func (m *Model) renderStatusPanel() string {
 		if m.focusStatus && i == m.selected {
 			name = lipgloss.NewStyle().Bold(true).Render(name)
 		}
-		statusLines = append(statusLines, fmt.Sprintf("%s %-19s %-7s %5s", checked, name, statusDot(row.Status), portStr(row.Port)))
+		port := portStr(row.Port)
+		if row.Task {
+			port = "task"
+		}
+		line := fmt.Sprintf("%s %-19s %-7s %5s", checked, name, m.theme.statusDot(row), port)
+		if i := m.paneIndex(row.Name); i >= 0 {
+			line += lipgloss.NewStyle().Foreground(m.theme.Dim).Render(fmt.Sprintf(" ▣%d", i+1))
+		}
+		if n := m.errCounts[row.Name]; n > 0 {
+			line += lipgloss.NewStyle().Foreground(m.theme.Error).Render(fmt.Sprintf(" ✗%d", n))
+		}
+		line += m.pgSessionsLabel(row.Name)
+		statusLines = append(statusLines, line)
 	}
+	statusLines = append(statusLines, m.renderLevelFilter())
 	content := strings.Join(statusLines, "\n")
-	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).Padding(0, 1)
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(m.theme.Border).Padding(0, 1)
 	return box.Width(52).Render(content)
 }
 
+// renderLevelFilter shows the level filter steps with the active one
+// highlighted; L cycles through them.
+func (m *Model) renderLevelFilter() string {
+	dim := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	parts := []string{dim.Render("Level")}
+	for _, l := range levelFilterSteps {
+		label := levelFilterLabel(l)
+		if l == m.minLevel {
+			parts = append(parts, lipgloss.NewStyle().Bold(true).Reverse(true).Render(" "+label+" "))
+		} else {
+			parts = append(parts, dim.Render(" "+label+" "))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 func (m *Model) renderRightPanel() string {
 	status := m.renderStatusPanel()
 	panels := []string{status}
@@ -641,6 +1078,9 @@ func (m *Model) renderRightPanel() string {
 	if m.dockerEnabled {
 		panels = append(panels, m.renderDockerPanel())
 	}
+	if len(m.procStats) > 0 || m.procErr != "" {
+		panels = append(panels, m.renderProcessPanel())
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, panels...)
 }
 
@@ -662,13 +1102,13 @@ func (m *Model) renderPostgresViewport() {
 		return
 	}
 	if m.pgStats.Error != "" {
-		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("Error: "+m.pgStats.Error))
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.Error).Render("Error: "+m.pgStats.Error))
 		m.pgViewport.SetContent(strings.Join(lines, "\n"))
 		return
 	}
 	sq := m.pgStats.StuckQueries
-	if len(sq) == 0 {
-		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("No stuck queries."))
+	if len(sq) == 0 && len(m.pgStats.LockTrees) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.OK).Render("No stuck queries."))
 		lines = append(lines, "")
 		lines = append(lines, "Idle in transaction, long-running (>30s), and blocking backends will appear here.")
 		m.pgViewport.SetContent(strings.Join(lines, "\n"))
@@ -681,19 +1121,32 @@ func (m *Model) renderPostgresViewport() {
 		sepLen = 80
 	}
 	lines = append(lines, strings.Repeat("─", sepLen))
-	for _, q := range sq {
-		reasonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	m.selectedStuckQuery()
+	for i, q := range sq {
+		reasonStyle := lipgloss.NewStyle().Foreground(m.theme.Warn)
 		if q.Reason == "blocking" {
-			reasonStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+			reasonStyle = lipgloss.NewStyle().Foreground(m.theme.Error)
 		}
 		line := fmt.Sprintf("%-9d %-24s %-10s %s", q.PID, reasonStyle.Render(q.Reason), q.Duration, q.Query)
+		if i == m.pgSelected {
+			line = lipgloss.NewStyle().Reverse(true).Render(fmt.Sprintf("%-9d %-24s %-10s %s", q.PID, q.Reason, q.Duration, q.Query))
+		}
+		lines = append(lines, lineStyle.Render(line))
+	}
+	for _, line := range m.lockTreeLines() {
 		lines = append(lines, lineStyle.Render(line))
 	}
 	m.pgViewport.SetContent(strings.Join(lines, "\n"))
+	selectedRow := m.pgSelected + 2 // header and separator
+	if selectedRow < m.pgViewport.YOffset {
+		m.pgViewport.SetYOffset(selectedRow)
+	} else if selectedRow >= m.pgViewport.YOffset+m.pgViewport.Height {
+		m.pgViewport.SetYOffset(selectedRow - m.pgViewport.Height + 1)
+	}
 }
 
 func (m *Model) renderPostgresPanel() string {
-	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).Padding(0, 1)
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(m.theme.Border).Padding(0, 1)
 	title := "Postgres"
 	lines := []string{lipgloss.NewStyle().Bold(true).Render(title)}
 
@@ -703,26 +1156,26 @@ func (m *Model) renderPostgresPanel() string {
 	}
 	s := m.pgStats
 	if s.Error != "" {
-		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("error: "+s.Error))
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.Error).Render("error: "+s.Error))
 		return box.Width(52).Render(strings.Join(lines, "\n"))
 	}
 
 	connStr := fmt.Sprintf("%d / %d", s.Connections, s.MaxConnections)
 	if s.Connections > 0 && s.MaxConnections > 0 && float64(s.Connections)/float64(s.MaxConnections) > 0.9 {
-		connStr = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(connStr)
+		connStr = lipgloss.NewStyle().Foreground(m.theme.Warn).Render(connStr)
 	}
 	lines = append(lines, fmt.Sprintf("Connections   %s", connStr))
 	lines = append(lines, fmt.Sprintf("Idle in tx    %d", s.IdleInTx))
 	if s.IdleInTx > 0 {
-		lines[len(lines)-1] = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(lines[len(lines)-1])
+		lines[len(lines)-1] = lipgloss.NewStyle().Foreground(m.theme.Warn).Render(lines[len(lines)-1])
 	}
 	lines = append(lines, fmt.Sprintf("Long-running  %d", s.LongRunning))
 	if s.LongRunning > 0 {
-		lines[len(lines)-1] = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(lines[len(lines)-1])
+		lines[len(lines)-1] = lipgloss.NewStyle().Foreground(m.theme.Warn).Render(lines[len(lines)-1])
 	}
 	lines = append(lines, fmt.Sprintf("Blocking      %d", s.BlockingLocks))
 	if s.BlockingLocks > 0 {
-		lines[len(lines)-1] = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render(lines[len(lines)-1])
+		lines[len(lines)-1] = lipgloss.NewStyle().Foreground(m.theme.Error).Render(lines[len(lines)-1])
 	}
 	if s.CacheHitRatio > 0 {
 		lines = append(lines, fmt.Sprintf("Cache hit     %.1f%%", s.CacheHitRatio*100))
@@ -736,7 +1189,7 @@ func (m *Model) renderPostgresPanel() string {
 const dockerWarnRatio = 0.85 // show in red when usage >= 85% of limit
 
 func (m *Model) renderDockerPanel() string {
-	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).Padding(0, 1)
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(m.theme.Border).Padding(0, 1)
 	title := "Docker"
 	lines := []string{lipgloss.NewStyle().Bold(true).Render(title)}
 
@@ -746,7 +1199,7 @@ func (m *Model) renderDockerPanel() string {
 	}
 	s := m.dockerStats
 	if s.Error != "" {
-		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("error: "+s.Error))
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.Error).Render("error: "+s.Error))
 		return box.Width(52).Render(strings.Join(lines, "\n"))
 	}
 
@@ -756,7 +1209,7 @@ func (m *Model) renderDockerPanel() string {
 		ramStr = ramStr + " / " + dockerstats.FormatSize(s.RAMLimitBytes)
 		if s.RAMUsedBytes > 0 {
 			if r := float64(s.RAMUsedBytes) / float64(s.RAMLimitBytes); r >= dockerWarnRatio {
-				ramStr = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render(ramStr)
+				ramStr = lipgloss.NewStyle().Foreground(m.theme.Error).Render(ramStr)
 			}
 		}
 	}
@@ -772,7 +1225,7 @@ func (m *Model) renderDockerPanel() string {
 		diskStr = diskStr + " (limit " + dockerstats.FormatSize(s.DiskLimitBytes) + ")"
 		if s.DiskUsedBytes > 0 {
 			if r := float64(s.DiskUsedBytes) / float64(s.DiskLimitBytes); r >= dockerWarnRatio {
-				diskStr = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render(diskStr)
+				diskStr = lipgloss.NewStyle().Foreground(m.theme.Error).Render(diskStr)
 			}
 		}
 	} else {
@@ -866,12 +1319,57 @@ func (m *Model) sortedRows() []ServiceRow {
 }
 
 func (m *Model) renderFooter() string {
-	keys := "keys: q quit • tab focus • / filter • space toggle • j/k scroll • g/G top/bottom • f follow • Y copy"
+	if m.pgConfirm != nil {
+		prompt := lipgloss.NewStyle().Foreground(m.theme.Warn).Bold(true).Padding(0, 1)
+		return prompt.Width(m.width).Render(m.pgConfirm.prompt())
+	}
+	k := m.keys.label
+	tabs := k("quit") + " quit • " + k("help") + " help • " + k("palette") + " commands • " + k("tab-logs") + " App Logs • "
 	if m.postgresURL != "" {
-		keys = "keys: q quit • 1 App Logs • 2 Postgres • tab focus • / filter • space toggle • j/k scroll • g/G top/bottom • f follow • Y copy"
+		tabs += k("tab-postgres") + " Postgres • "
+	}
+	tabs += k("tab-errors") + " Errors"
+	scroll := k("down") + "/" + k("up") + " scroll • " + k("top") + "/" + k("bottom") + " top/bottom • " + k("follow") + " follow"
+	keys := "keys: " + tabs + " • " + k("focus") + " focus • " + k("filter") + " filter • " + k("toggle") + " toggle • " + scroll +
+		" • " + k("times") + " times • " + k("jump") + " jump • " + k("search") + " search • " + k("copy") + " copy • " +
+		k("export") + "/" + k("export-all") + " export • " + k("expand") + " expand"
+	if m.activeTab == TabPostgres {
+		keys = "keys: " + tabs + " • " + k("down") + "/" + k("up") + " select • " + k("pg-cancel") + " cancel query • " + k("pg-terminate") + " terminate backend"
+		if len(m.lockTrees()) > 0 {
+			keys += " • " + k("pg-root") + " terminate root blocker"
+		}
+	}
+	if m.activeTab == TabErrors {
+		keys = "keys: " + tabs + " • " + k("down") + "/" + k("up") + " select • " + k("detail") + " jump to logs • " + k("copy-traceback") + " copy traceback"
 	}
 	if m.focusStatus {
-		keys = "keys: q quit • tab focus • / filter • space toggle • a all • n none • j/k select • g/G top/bottom • esc clear filter"
+		keys = "keys: " + k("quit") + " quit • " + k("help") + " help • " + k("focus") + " focus • " + k("filter") + " filter • " + k("toggle") + " toggle • " +
+			k("select-all") + " all • " + k("select-none") + " none • " + k("detail") + " detail • " + k("pin") + " pin pane • " + k("split") + " split • " +
+			k("down") + "/" + k("up") + " select • " + k("top") + "/" + k("bottom") + " top/bottom • " + k("close") + " clear filter"
+	} else if m.split && m.activeTab == TabAppLogs {
+		keys = "keys: " + k("quit") + " quit • " + k("help") + " help • " + k("focus") + " focus • " + k("prev-pane") + "/" + k("next-pane") + " pane • " + scroll + " • " + k("split") + " combined view"
+	}
+	keys += " • " + k("level") + " level: " + levelFilterLabel(m.minLevel)
+	if m.watching {
+		if m.watchPaused {
+			keys += " • " + k("watch") + " auto-restart: paused"
+		} else {
+			keys += " • " + k("watch") + " auto-restart: on"
+		}
+	}
+	if m.notice != "" {
+		keys += " • " + m.notice
+	}
+
+	if m.jumpMode {
+		keys += " • jump to: " + m.jumpText + " (minutes ago, 10m or HH:MM)"
+	} else if m.jumpErr != "" {
+		keys += " • jump: " + m.jumpErr
+	}
+	if m.searchMode {
+		keys += " • search: " + m.searchText + " (typing...)"
+	} else if m.search != nil {
+		keys += " • search: " + m.searchStatus() + " " + k("next-match") + "/" + k("prev-match") + " next/prev"
 	}
 	if m.filterText != "" {
 		keys += " • filter: " + m.filterText
@@ -879,7 +1377,7 @@ func (m *Model) renderFooter() string {
 			keys += " (typing...)"
 		}
 	}
-	style := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Padding(0, 1)
+	style := lipgloss.NewStyle().Foreground(m.theme.Dim).Padding(0, 1)
 	return style.Width(m.width).Render(keys)
 }
 
@@ -887,22 +1385,33 @@ func (m *Model) colorFor(service string) lipgloss.Color {
 	if color, ok := m.colors[service]; ok {
 		return color
 	}
-	palette := []lipgloss.Color{"2", "3", "4", "5", "6", "9", "10", "11", "12", "13"}
-	color := palette[len(m.colors)%len(palette)]
+	color := m.theme.Palette[len(m.colors)%len(m.theme.Palette)]
 	m.colors[service] = color
 	return color
 }
 
-func statusDot(status string) string {
-	switch status {
+func (t Theme) statusDot(row ServiceRow) string {
+	switch row.Status {
 	case "running":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("● RUN")
+		return lipgloss.NewStyle().Foreground(t.OK).Render("● RUN")
 	case "starting":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("○ ...")
+		return lipgloss.NewStyle().Foreground(t.Warn).Render("○ ...")
 	case "error":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✗ ERR")
+		return lipgloss.NewStyle().Foreground(t.Error).Render("✗ ERR")
 	case "stopped":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("○ ---")
+		return lipgloss.NewStyle().Foreground(t.Muted).Render("○ ---")
+	case "oom":
+		return lipgloss.NewStyle().Foreground(t.Error).Render("✗ OOM")
+	case "pending":
+		return lipgloss.NewStyle().Foreground(t.Muted).Render("○ WAIT")
+	case "succeeded":
+		return lipgloss.NewStyle().Foreground(t.OK).Render("✓ DONE")
+	case "failed":
+		label := "✗ FAIL"
+		if row.ExitCode != nil {
+			label = fmt.Sprintf("✗ EXIT %d", *row.ExitCode)
+		}
+		return lipgloss.NewStyle().Foreground(t.Error).Render(label)
 	default:
 		return ""
 	}
@@ -1047,8 +1556,13 @@ func (m *Model) copyLogSelection() bool {
 	if e > len(m.lastLogContent) {
 		e = len(m.lastLogContent)
 	}
-	selected := m.lastLogContent[s:e]
-	plain := stripANSI(selected)
+	var plain string
+	if from, to := m.rowAtOffset(s), m.rowAtOffset(e); m.selectionTouchesJSON(from, to) {
+		// JSON lines are shown compacted; copy the raw line instead.
+		plain = m.rowsText(from, to)
+	} else {
+		plain = stripANSI(m.lastLogContent[s:e])
+	}
 	if plain == "" {
 		return true
 	}
@@ -1074,8 +1588,7 @@ func (m *Model) copyVisibleLogs() bool {
 	if start >= end {
 		return true
 	}
-	visible := strings.Join(lines[start:end], "\n")
-	plain := stripANSI(visible)
+	plain := m.rowsText(start, end-1)
 	if plain != "" {
 		_ = clipboard.WriteAll(plain)
 	}
@@ -0,0 +1,202 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteCommands is the enabled commands with a title plus one entry per
+// service and per stuck Postgres backend.
+func (m *Model) paletteCommands() []command {
+	var out []command
+	for _, c := range commands {
+		if c.title != "" && (c.enabled == nil || c.enabled(m)) {
+			out = append(out, c)
+		}
+	}
+	for _, row := range m.sortedRows() {
+		name := row.Name
+		if m.requests != nil && !row.Task {
+			out = append(out, command{title: "Restart " + name, run: func(m *Model) tea.Cmd {
+				m.sendRequest(Request{Action: RequestRestart, Service: name})
+				return nil
+			}})
+		}
+		out = append(out, command{title: "Toggle logs of " + name, run: func(m *Model) tea.Cmd {
+			m.filters[name] = !m.filters[name]
+			return nil
+		}})
+		if m.describe != nil {
+			out = append(out, command{title: "Service detail: " + name, run: func(m *Model) tea.Cmd {
+				m.selectService(name)
+				m.focusStatus = true
+				return m.openServiceInfo()
+			}})
+		}
+		out = append(out, command{title: "Pin to split view: " + name, run: func(m *Model) tea.Cmd {
+			m.selectService(name)
+			m.togglePin()
+			return nil
+		}})
+	}
+	if m.pgStats != nil && m.postgresURL != "" {
+		for _, q := range m.pgStats.StuckQueries {
+			q := q
+			desc := fmt.Sprintf("%d (%s) %s", q.PID, q.Reason, truncate(strings.Join(strings.Fields(q.Query), " "), 40))
+			out = append(out, command{title: "Cancel query of Postgres backend " + desc, run: func(m *Model) tea.Cmd {
+				m.confirmPgAction(q, false)
+				return nil
+			}}, command{title: "Terminate Postgres backend " + desc, run: func(m *Model) tea.Cmd {
+				m.confirmPgAction(q, true)
+				return nil
+			}})
+		}
+		for _, root := range m.pgStats.LockTrees {
+			root := root
+			out = append(out, command{title: fmt.Sprintf("Terminate root blocker %d (blocks %d)", root.PID, root.Count()), run: func(m *Model) tea.Cmd {
+				m.confirmTerminateRoot(root)
+				return nil
+			}})
+		}
+	}
+	return out
+}
+
+// selectService moves the status panel selection to name, clearing a name
+// filter that hides it.
+func (m *Model) selectService(name string) {
+	for i, row := range m.sortedRows() {
+		if row.Name == name {
+			m.filterText = ""
+			m.selected = i
+			return
+		}
+	}
+}
+
+// fuzzyScore reports whether the runes of pattern appear in text in order,
+// ignoring case. Runs of consecutive runes and word starts score higher.
+func fuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+	score, pi, prev := 0, 0, -2
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 3
+		}
+		if ti == 0 || strings.ContainsRune(" :-_/", t[ti-1]) {
+			score += 2
+		}
+		prev = ti
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
+
+// paletteMatches filters and ranks the palette commands by the typed text.
+func (m *Model) paletteMatches() []command {
+	all := m.paletteCommands()
+	if m.paletteText == "" {
+		return all
+	}
+	type scored struct {
+		command
+		score int
+	}
+	var hits []scored
+	for _, c := range all {
+		if s, ok := fuzzyScore(m.paletteText, c.title); ok {
+			hits = append(hits, scored{c, s})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	out := make([]command, len(hits))
+	for i, h := range hits {
+		out[i] = h.command
+	}
+	return out
+}
+
+func (m *Model) openPalette() {
+	m.paletteMode = true
+	m.paletteText = ""
+	m.paletteSel = 0
+}
+
+// paletteKey handles keys while the palette is open.
+func (m *Model) paletteKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.paletteMode = false
+	case "enter":
+		m.paletteMode = false
+		matches := m.paletteMatches()
+		if m.paletteSel < len(matches) {
+			return matches[m.paletteSel].run(m)
+		}
+	case "up", "ctrl+p":
+		if m.paletteSel > 0 {
+			m.paletteSel--
+		}
+	case "down", "ctrl+n":
+		if m.paletteSel < len(m.paletteMatches())-1 {
+			m.paletteSel++
+		}
+	case "backspace", "ctrl+h":
+		if len(m.paletteText) > 0 {
+			m.paletteText = m.paletteText[:len(m.paletteText)-1]
+			m.paletteSel = 0
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.paletteText += msg.String()
+			m.paletteSel = 0
+		}
+	}
+	return nil
+}
+
+// paletteView is the palette shown in place of the left panel content.
+func (m *Model) paletteView() string {
+	dim := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	lines := []string{
+		dim.Render("Command palette ─ enter run • up/down select • esc close"),
+		"> " + m.paletteText + "▌",
+	}
+	matches := m.paletteMatches()
+	if len(matches) == 0 {
+		lines = append(lines, dim.Render("  no matching commands"))
+	}
+	rows := m.viewport.Height - 2
+	if rows < 1 {
+		rows = 1
+	}
+	start := 0
+	if m.paletteSel >= rows {
+		start = m.paletteSel - rows + 1
+	}
+	selected := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Active)
+	for i := start; i < len(matches) && i < start+rows; i++ {
+		title := matches[i].title
+		if matches[i].binding != "" {
+			title += dim.Render("  " + m.keys.label(matches[i].binding))
+		}
+		if i == m.paletteSel {
+			lines = append(lines, selected.Render("› "+matches[i].title)+strings.TrimPrefix(title, matches[i].title))
+		} else {
+			lines = append(lines, "  "+title)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func typeKeys(m *Model, s string) {
+	for _, r := range s {
+		if r == ' ' {
+			m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}})
+			continue
+		}
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func Test_fuzzyScore(t *testing.T) {
+	if _, ok := fuzzyScore("ipa", "Restart api"); ok {
+		t.Error("ipa should not match Restart api out of order")
+	}
+	restart, ok1 := fuzzyScore("rest api", "Restart api")
+	pin, ok2 := fuzzyScore("rest api", "Pin to split view: api")
+	if !ok1 || ok2 && pin >= restart {
+		t.Errorf("Restart api = %d/%v, Pin = %d/%v", restart, ok1, pin, ok2)
+	}
+	if s, ok := fuzzyScore("ERR", "Switch to Errors"); !ok || s == 0 {
+		t.Error("matching should ignore case")
+	}
+}
+
+func Test_palette(t *testing.T) {
+	requests := make(chan Request, 1)
+	rows := []ServiceRow{{Name: "api", Status: "running"}, {Name: "migrate", Status: "succeeded", Task: true}}
+	m := NewModel(nil, nil, rows, Options{Requests: requests})
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	if !m.paletteMode {
+		t.Fatal(": should open the palette")
+	}
+	typeKeys(m, "errors")
+	if view := stripANSI(m.View()); !strings.Contains(view, "› Switch to Errors") {
+		t.Errorf("palette should select the best match:\n%s", view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.paletteMode || m.activeTab != TabErrors {
+		t.Errorf("enter should run the command and close: mode=%v tab=%d", m.paletteMode, m.activeTab)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	typeKeys(m, "restart")
+	for _, c := range m.paletteMatches() {
+		if strings.Contains(c.title, "migrate") {
+			t.Errorf("tasks cannot be restarted: %q", c.title)
+		}
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	select {
+	case req := <-requests:
+		if req.Action != RequestRestart || req.Service != "api" {
+			t.Errorf("request = %+v", req)
+		}
+	default:
+		t.Error("restart should send a request")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.paletteMode || m.interrupted {
+		t.Error("typing q in the palette should not quit, esc should close it")
+	}
+}
+
+func Test_commandsCoverBindings(t *testing.T) {
+	for _, b := range defaultBindings {
+		found := false
+		for _, c := range commands {
+			found = found || c.binding == b.action
+		}
+		if !found {
+			t.Errorf("no command for action %s", b.action)
+		}
+	}
+}
+
+func Test_boundCommand_context(t *testing.T) {
+	m := NewModel(nil, nil, []ServiceRow{{Name: "api", Status: "running"}}, Options{})
+
+	m.focusStatus = true
+	if c := m.boundCommand("detail"); c == nil || c.title != "Toggle service detail" {
+		t.Errorf("detail with the services focused = %+v", c)
+	}
+	m.focusStatus = false
+	m.activeTab = TabErrors
+	if c := m.boundCommand("detail"); c == nil || c.title != "Jump to the selected error" {
+		t.Errorf("detail on the Errors tab = %+v", c)
+	}
+	titles := []string{}
+	for _, c := range m.paletteCommands() {
+		titles = append(titles, c.title)
+	}
+	if all := strings.Join(titles, "\n"); !strings.Contains(all, "Jump to the selected error") || strings.Contains(all, "Toggle service detail") {
+		t.Errorf("palette should list only enabled commands:\n%s", all)
+	}
+	m.activeTab = TabAppLogs
+	if c := m.boundCommand("detail"); c != nil {
+		t.Errorf("detail on the App Logs tab = %q, want none", c.title)
+	}
+}
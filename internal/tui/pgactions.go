@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"floppy-go/internal/postgresstats"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pgAction is a cancel or terminate waiting for confirmation.
+type pgAction struct {
+	query     postgresstats.StuckQuery
+	terminate bool
+}
+
+func (a pgAction) prompt() string {
+	if a.terminate {
+		return fmt.Sprintf("terminate backend %d (%s)? y/n", a.query.PID, a.query.Reason)
+	}
+	return fmt.Sprintf("cancel the query of backend %d (%s)? y/n", a.query.PID, a.query.Reason)
+}
+
+// noticeMsg is shown in the footer when a background action finishes.
+type noticeMsg string
+
+// run signals the backend; the result comes back as a footer notice.
+func (a pgAction) run(url string) tea.Cmd {
+	return func() tea.Msg {
+		pid := a.query.PID
+		if a.terminate {
+			if err := postgresstats.TerminateBackend(context.Background(), url, pid); err != nil {
+				return noticeMsg(err.Error())
+			}
+			return noticeMsg(fmt.Sprintf("terminated backend %d", pid))
+		}
+		if err := postgresstats.CancelBackend(context.Background(), url, pid); err != nil {
+			return noticeMsg(err.Error())
+		}
+		return noticeMsg(fmt.Sprintf("cancelled the query of backend %d", pid))
+	}
+}
+
+func (m *Model) stuckQueries() []postgresstats.StuckQuery {
+	if m.pgStats == nil || m.pgStats.Error != "" {
+		return nil
+	}
+	return m.pgStats.StuckQueries
+}
+
+// selectedStuckQuery clamps the Postgres tab selection and returns the row.
+func (m *Model) selectedStuckQuery() *postgresstats.StuckQuery {
+	sq := m.stuckQueries()
+	if len(sq) == 0 {
+		m.pgSelected = 0
+		return nil
+	}
+	if m.pgSelected >= len(sq) {
+		m.pgSelected = len(sq) - 1
+	}
+	if m.pgSelected < 0 {
+		m.pgSelected = 0
+	}
+	return &sq[m.pgSelected]
+}
+
+func (m *Model) movePgSelection(delta int) {
+	m.pgSelected += delta
+	m.selectedStuckQuery()
+}
+
+// setPgStats replaces the Postgres snapshot, keeping the selection on the
+// same backend when it is still listed.
+func (m *Model) setPgStats(s postgresstats.Stats) {
+	var pid int64
+	if q := m.selectedStuckQuery(); q != nil {
+		pid = q.PID
+	}
+	m.pgStats = &s
+	for i, q := range m.stuckQueries() {
+		if q.PID == pid {
+			m.pgSelected = i
+			return
+		}
+	}
+	m.selectedStuckQuery()
+}
+
+func (m *Model) confirmPgAction(q postgresstats.StuckQuery, terminate bool) {
+	m.pgConfirm = &pgAction{query: q, terminate: terminate}
+}
+
+// pgConfirmKey answers the confirmation prompt: y runs the action, any
+// other key drops it.
+func (m *Model) pgConfirmKey(msg tea.KeyMsg) tea.Cmd {
+	a := *m.pgConfirm
+	m.pgConfirm = nil
+	if msg.String() != "y" {
+		return nil
+	}
+	return a.run(m.postgresURL)
+}
+
+func (m *Model) lockTrees() []*postgresstats.LockNode {
+	if m.pgStats == nil || m.pgStats.Error != "" {
+		return nil
+	}
+	return m.pgStats.LockTrees
+}
+
+// rootBlocker is the root of the lock tree the selected backend is part of,
+// or the root blocking the most backends when the selection is not waiting
+// on a lock.
+func (m *Model) rootBlocker() *postgresstats.LockNode {
+	trees := m.lockTrees()
+	if len(trees) == 0 {
+		return nil
+	}
+	if q := m.selectedStuckQuery(); q != nil {
+		for _, root := range trees {
+			if root.Contains(q.PID) {
+				return root
+			}
+		}
+	}
+	return trees[0]
+}
+
+// confirmTerminateRoot asks to terminate a root blocker, which releases
+// every lock it holds.
+func (m *Model) confirmTerminateRoot(root *postgresstats.LockNode) {
+	m.confirmPgAction(postgresstats.StuckQuery{
+		PID:      root.PID,
+		State:    root.State,
+		Reason:   fmt.Sprintf("root blocker of %d", root.Count()),
+		Query:    root.Query,
+		Duration: root.Duration,
+		AppName:  root.AppName,
+	}, true)
+}
+
+// lockTreeLines renders the blocker trees, the root the pg-root key would
+// terminate marked with ▶.
+func (m *Model) lockTreeLines() []string {
+	trees := m.lockTrees()
+	if len(trees) == 0 {
+		return nil
+	}
+	target := m.rootBlocker()
+	dim := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	lines := []string{"", lipgloss.NewStyle().Bold(true).Render("Lock waits")}
+	var walk func(n *postgresstats.LockNode, prefix string, last bool)
+	walk = func(n *postgresstats.LockNode, prefix string, last bool) {
+		branch, indent := "├─ ", "│  "
+		if last {
+			branch, indent = "└─ ", "   "
+		}
+		lines = append(lines, prefix+branch+lipgloss.NewStyle().Foreground(m.theme.Warn).Render(n.Summary())+"  "+dim.Render(oneLine(n.Query)))
+		for i, c := range n.Blocked {
+			walk(c, prefix+indent, i == len(n.Blocked)-1)
+		}
+	}
+	for _, root := range trees {
+		marker := "  "
+		if root == target {
+			marker = "▶ "
+		}
+		lines = append(lines, marker+lipgloss.NewStyle().Foreground(m.theme.Error).Bold(true).Render(root.Summary())+
+			dim.Render(fmt.Sprintf("  blocks %d", root.Count())))
+		if root.Query != "" {
+			lines = append(lines, "  "+dim.Render(oneLine(root.Query)))
+		}
+		for i, c := range root.Blocked {
+			walk(c, "  ", i == len(root.Blocked)-1)
+		}
+	}
+	return lines
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"floppy-go/internal/postgresstats"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func Test_pgActions(t *testing.T) {
+	m := NewModel(nil, nil, nil, Options{PostgresURL: "postgres://localhost/x"})
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m.activeTab = TabPostgres
+	m.setPgStats(postgresstats.Stats{StuckQueries: []postgresstats.StuckQuery{
+		{PID: 11, Reason: "idle in transaction", Query: "BEGIN"},
+		{PID: 22, Reason: "long-running", Query: "SELECT pg_sleep(60)"},
+	}})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if q := m.selectedStuckQuery(); q == nil || q.PID != 22 {
+		t.Fatalf("j should select the second backend, got %+v", q)
+	}
+	m.setPgStats(postgresstats.Stats{StuckQueries: []postgresstats.StuckQuery{
+		{PID: 22, Reason: "long-running", Query: "SELECT pg_sleep(60)"},
+		{PID: 33, Reason: "blocking", Query: "UPDATE t"},
+	}})
+	if q := m.selectedStuckQuery(); q == nil || q.PID != 22 {
+		t.Fatalf("selection should follow the backend across refreshes, got %+v", q)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	if m.pgConfirm == nil || !m.pgConfirm.terminate || m.pgConfirm.query.PID != 22 {
+		t.Fatalf("K should ask to terminate 22, got %+v", m.pgConfirm)
+	}
+	if !strings.Contains(stripANSI(m.View()), "terminate backend 22 (long-running)? y/n") {
+		t.Error("footer should show the confirmation prompt")
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}); cmd != nil || m.pgConfirm != nil {
+		t.Error("n should drop the action")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if m.pgConfirm == nil || m.pgConfirm.terminate {
+		t.Fatalf("c should ask to cancel, got %+v", m.pgConfirm)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil || m.pgConfirm != nil {
+		t.Error("y should run the action")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	typeKeys(m, "terminate 33")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.pgConfirm == nil || m.pgConfirm.query.PID != 33 {
+		t.Errorf("palette terminate should ask for confirmation, got %+v", m.pgConfirm)
+	}
+
+	m.setPgStats(postgresstats.Stats{
+		StuckQueries: []postgresstats.StuckQuery{
+			{PID: 22, Reason: "long-running", Query: "SELECT pg_sleep(60)"},
+			{PID: 33, Reason: "blocking", Query: "UPDATE t"},
+		},
+		LockTrees: []*postgresstats.LockNode{{PID: 44, State: "idle in transaction", Holds: []string{"RowExclusiveLock on t"},
+			Blocked: []*postgresstats.LockNode{{PID: 33, Waits: []string{"RowExclusiveLock on t"}}}}},
+	})
+	m.pgConfirm = nil
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "▶ 44  idle in transaction  holds RowExclusiveLock on t  blocks 1") || !strings.Contains(view, "└─ 33  waits for") {
+		t.Errorf("lock tree missing from the Postgres tab:\n%s", view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	if m.pgConfirm == nil || !m.pgConfirm.terminate || m.pgConfirm.query.PID != 44 {
+		t.Errorf("B should ask to terminate the root blocker 44, got %+v", m.pgConfirm)
+	}
+}
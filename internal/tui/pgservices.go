@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"floppy-go/internal/postgresstats"
+	"floppy-go/internal/procstats"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pgServiceSessions attributes Postgres sessions to services; pids are the
+// process groups of running services, whose sockets identify connections
+// that carry no application_name.
+func pgServiceSessions(sessions []postgresstats.Session, names []string, pids map[string]int) map[string]postgresstats.ServiceSessions {
+	owners := map[int]string{}
+	if len(sessions) > 0 && len(pids) > 0 {
+		byPgid := map[int]string{}
+		pgids := make([]int, 0, len(pids))
+		for name, pid := range pids {
+			byPgid[pid] = name
+			pgids = append(pgids, pid)
+		}
+		ports, _ := procstats.GroupPorts(pgids)
+		for port, pgid := range ports {
+			owners[port] = byPgid[pgid]
+		}
+	}
+	return postgresstats.SessionsByService(sessions, names, owners)
+}
+
+// longTx is when a service's longest transaction is shown as a warning,
+// matching the long-running threshold of the Postgres tab.
+const longTx = 30 * time.Second
+
+// pgSessionsLabel is the status panel suffix for a service's Postgres
+// sessions, kept short to fit the row: "db3 i1 2m" is three connections,
+// one idle in transaction and a transaction open for two minutes.
+func (m *Model) pgSessionsLabel(name string) string {
+	if m.pgStats == nil || m.pgStats.Error != "" {
+		return ""
+	}
+	s, ok := m.pgStats.Services[name]
+	if !ok {
+		return ""
+	}
+	label := fmt.Sprintf(" db%d", s.Connections)
+	if s.IdleInTx > 0 {
+		label += fmt.Sprintf(" i%d", s.IdleInTx)
+	}
+	if s.LongestTx >= time.Second {
+		label += " " + shortDuration(s.LongestTx)
+	}
+	style := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	if s.IdleInTx > 0 || s.LongestTx >= longTx {
+		style = lipgloss.NewStyle().Foreground(m.theme.Warn)
+	}
+	return style.Render(label)
+}
+
+// shortDuration renders d in its largest unit, e.g. "45s", "2m", "3h".
+func shortDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours())/24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"floppy-go/internal/postgresstats"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func Test_pgSessionsLabel(t *testing.T) {
+	m := NewModel(nil, nil, []ServiceRow{{Name: "api", Status: "running", Port: 8000}, {Name: "web", Status: "running"}},
+		Options{PostgresURL: "postgres://localhost/x"})
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	sessions := []postgresstats.Session{
+		{PID: 1, AppName: "api", State: "idle in transaction", TxAge: 2 * time.Minute},
+		{PID: 2, Database: "api_test", State: "idle"},
+	}
+	m.setPgStats(postgresstats.Stats{Sessions: sessions, Services: pgServiceSessions(sessions, []string{"api", "web"}, nil)})
+
+	if got := stripANSI(m.pgSessionsLabel("api")); got != " db2 i1 2m" {
+		t.Errorf("api label = %q", got)
+	}
+	if got := m.pgSessionsLabel("web"); got != "" {
+		t.Errorf("web has no sessions, label = %q", got)
+	}
+	if !strings.Contains(stripANSI(m.renderStatusPanel()), "8000 db2 i1 2m") {
+		t.Errorf("status panel:\n%s", stripANSI(m.renderStatusPanel()))
+	}
+}
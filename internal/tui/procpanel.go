@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"floppy-go/internal/procstats"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// procHistoryLen is how many samples are kept per service; at the 3s fetch
+// cadence this covers the last three minutes.
+const procHistoryLen = 60
+
+// sparkWidth is the number of samples drawn in each sparkline.
+const sparkWidth = 12
+
+// Runaway memory: flag a service whose RSS passes runawayRSSBytes (or
+// runawayLimitRatio of its configured memory limit), or whose RSS has grown
+// on every sample of the last runawayWindow samples by at least
+// runawayGrowth.
+const (
+	runawayRSSBytes   = 2 << 30
+	runawayLimitRatio = 0.85
+	runawayWindow     = 20
+	runawayGrowth     = 1.5
+)
+
+// procSnapshot is one round of process stats, keyed by service name.
+type procSnapshot struct {
+	usage map[string]procstats.Usage
+	err   string
+}
+
+type procHistory struct {
+	rss []int64
+}
+
+func (h *procHistory) add(u procstats.Usage) {
+	h.rss = append(h.rss, u.RSSBytes)
+	if len(h.rss) > procHistoryLen {
+		h.rss = h.rss[len(h.rss)-procHistoryLen:]
+	}
+}
+
+// runningPIDs returns the process group leader PID of each running service.
+// Services are started with Setpgid, so the PID is also the PGID.
+func (m *Model) runningPIDs() map[string]int {
+	out := map[string]int{}
+	for name, row := range m.statuses {
+		if row.Status == "running" && row.PID > 0 {
+			out[name] = row.PID
+		}
+	}
+	return out
+}
+
+func fetchProcStats(sampler *procstats.Sampler, pids map[string]int) procSnapshot {
+	out := procSnapshot{usage: map[string]procstats.Usage{}}
+	pgids := make([]int, 0, len(pids))
+	for _, pid := range pids {
+		pgids = append(pgids, pid)
+	}
+	usage, err := sampler.SampleAll(pgids)
+	if err != nil {
+		out.err = err.Error()
+		return out
+	}
+	for name, pid := range pids {
+		if u, ok := usage[pid]; ok {
+			out.usage[name] = u
+		}
+	}
+	return out
+}
+
+func (m *Model) drainProcStats() {
+	for {
+		select {
+		case s := <-m.procStatsCh:
+			m.procErr = s.err
+			m.procStats = s.usage
+			for name, u := range s.usage {
+				h, ok := m.procHistory[name]
+				if !ok {
+					h = &procHistory{}
+					m.procHistory[name] = h
+				}
+				h.add(u)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (m *Model) renderProcessPanel() string {
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(m.theme.Border).Padding(0, 1)
+	lines := []string{lipgloss.NewStyle().Bold(true).Render("Processes")}
+	if m.procErr != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.Dim).Render("unavailable: "+m.procErr))
+		return box.Width(52).Render(strings.Join(lines, "\n"))
+	}
+
+	lines = append(lines, fmt.Sprintf("%-13s %5s %6s %4s %3s %s", "Service", "CPU%", "RSS", "FDs", "Thr", "Memory"))
+	names := make([]string, 0, len(m.procStats))
+	for name := range m.procStats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	red := lipgloss.NewStyle().Foreground(m.theme.Error)
+	for _, name := range names {
+		u := m.procStats[name]
+		h := m.procHistory[name]
+		label := truncate(name, 13)
+		rss := procstats.FormatBytes(u.RSSBytes)
+		if h != nil && isRunaway(h.rss, m.statuses[name].MemoryLimit) {
+			label = red.Render(fmt.Sprintf("%-13s", truncate("!"+name, 13)))
+			rss = red.Render(fmt.Sprintf("%6s", rss))
+		} else {
+			label = fmt.Sprintf("%-13s", label)
+			rss = fmt.Sprintf("%6s", rss)
+		}
+		spark := ""
+		if h != nil {
+			spark = sparklineInt(h.rss, sparkWidth)
+		}
+		lines = append(lines, fmt.Sprintf("%s %5.1f %s %4d %3d %s", label, u.CPUPct, rss, u.OpenFDs, u.Threads, spark))
+	}
+	return box.Width(52).Render(strings.Join(lines, "\n"))
+}
+
+// isRunaway reports whether an RSS history looks like a memory leak or is
+// about to hit the service's memory limit (0 = no limit).
+func isRunaway(rss []int64, limit int64) bool {
+	if len(rss) == 0 {
+		return false
+	}
+	last := rss[len(rss)-1]
+	if last >= runawayRSSBytes {
+		return true
+	}
+	if limit > 0 && float64(last) >= float64(limit)*runawayLimitRatio {
+		return true
+	}
+	if len(rss) < runawayWindow {
+		return false
+	}
+	window := rss[len(rss)-runawayWindow:]
+	for i := 1; i < len(window); i++ {
+		if window[i] <= window[i-1] {
+			return false
+		}
+	}
+	return float64(last) >= float64(window[0])*runawayGrowth
+}
+
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// sparklineInt draws the last width values scaled between their min and max.
+func sparklineInt(values []int64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if hi > lo {
+			idx = int(float64(v-lo) / float64(hi-lo) * float64(len(sparkRunes)-1))
+		}
+		b.WriteRune(sparkRunes[idx])
+	}
+	return b.String()
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
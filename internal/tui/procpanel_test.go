@@ -0,0 +1,55 @@
+package tui
+
+import "testing"
+
+func Test_isRunaway(t *testing.T) {
+	growing := make([]int64, runawayWindow)
+	for i := range growing {
+		growing[i] = int64(100+10*i) << 20 // 100M -> 290M
+	}
+	flat := make([]int64, runawayWindow)
+	for i := range flat {
+		flat[i] = 300 << 20
+	}
+	dip := append([]int64(nil), growing...)
+	dip[runawayWindow/2] = dip[runawayWindow/2-1]
+
+	tests := []struct {
+		name  string
+		rss   []int64
+		limit int64
+		want  bool
+	}{
+		{"no samples", nil, 0, false},
+		{"over the absolute cap", []int64{runawayRSSBytes}, 0, true},
+		{"near the memory limit", []int64{900 << 20}, 1 << 30, true},
+		{"well under the memory limit", []int64{500 << 20}, 1 << 30, false},
+		{"steady growth over the window", growing, 0, true},
+		{"growth too short to judge", growing[:runawayWindow-1], 0, false},
+		{"flat", flat, 0, false},
+		{"growth with a pause", dip, 0, false},
+	}
+	for _, tt := range tests {
+		if got := isRunaway(tt.rss, tt.limit); got != tt.want {
+			t.Errorf("%s: isRunaway = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_sparklineInt(t *testing.T) {
+	tests := []struct {
+		values []int64
+		width  int
+		want   string
+	}{
+		{nil, 5, ""},
+		{[]int64{1, 2, 3, 4, 5, 6, 7, 8}, 8, "▁▂▃▄▅▆▇█"},
+		{[]int64{5, 5, 5}, 5, "▁▁▁"},
+		{[]int64{100, 0, 10, 20}, 3, "▁▄█"}, // last width values only
+	}
+	for _, tt := range tests {
+		if got := sparklineInt(tt.values, tt.width); got != tt.want {
+			t.Errorf("sparklineInt(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+		}
+	}
+}
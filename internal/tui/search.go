@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// logSearch is the active regex search over the rendered log view. Unlike
+// the / filter it hides nothing: matches are highlighted and n/N move
+// between them.
+type logSearch struct {
+	pattern string
+	re      *regexp.Regexp
+	err     string
+
+	matches []searchMatch // rebuilt on every render
+	// current match, kept by log sequence number so it survives new lines
+	// and buffer trimming; curNth counts matches within that line.
+	curSet bool
+	curSeq uint64
+	curNth int
+	cur    int // index into matches, -1 when the current match is not shown
+}
+
+type searchMatch struct {
+	row int
+	seq uint64
+	nth int
+}
+
+// compileSearch builds the regex for pattern. All-lowercase patterns match
+// case-insensitively (smart case).
+func compileSearch(pattern string) (*regexp.Regexp, error) {
+	if pattern == strings.ToLower(pattern) {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// startSearch applies the typed pattern and jumps to the most recent match.
+func (m *Model) startSearch(pattern string) {
+	if pattern == "" {
+		m.search = nil
+		return
+	}
+	s := &logSearch{pattern: pattern, cur: -1}
+	re, err := compileSearch(pattern)
+	if err != nil {
+		s.err = err.Error()
+	} else {
+		s.re = re
+	}
+	m.search = s
+	m.renderViewport()
+	if len(s.matches) > 0 {
+		m.gotoMatch(len(s.matches) - 1)
+	}
+}
+
+// searchStep moves to the next (delta 1) or previous (delta -1) match,
+// wrapping around, and turns follow off so the view stays put.
+func (m *Model) searchStep(delta int) {
+	s := m.search
+	if s == nil || len(s.matches) == 0 {
+		return
+	}
+	i := s.cur
+	if i < 0 {
+		i = len(s.matches) - 1
+		if delta > 0 {
+			i = -1
+		}
+	}
+	i = (i + delta + len(s.matches)) % len(s.matches)
+	m.gotoMatch(i)
+}
+
+func (m *Model) gotoMatch(i int) {
+	s := m.search
+	match := s.matches[i]
+	s.cur, s.curSeq, s.curNth, s.curSet = i, match.seq, match.nth, true
+	m.follow = false
+	top := match.row - m.viewport.Height/2
+	if top < 0 {
+		top = 0
+	}
+	m.renderViewport()
+	m.viewport.SetYOffset(top)
+}
+
+// searchStatus is the footer counter, e.g. "/timeout/ 3/17".
+func (m *Model) searchStatus() string {
+	s := m.search
+	if s == nil {
+		return ""
+	}
+	label := "/" + s.pattern + "/"
+	switch {
+	case s.err != "":
+		return label + " invalid: " + s.err
+	case len(s.matches) == 0:
+		return label + " no matches"
+	case s.cur < 0:
+		return fmt.Sprintf("%s %d matches", label, len(s.matches))
+	}
+	return fmt.Sprintf("%s %d/%d", label, s.cur+1, len(s.matches))
+}
+
+// resetMatches clears the match list before a render rebuilds it.
+func (s *logSearch) resetMatches() {
+	s.matches = s.matches[:0]
+	s.cur = -1
+}
+
+// matchText returns the text to render for a log line while searching: lines
+// with their own ANSI colors are shown plain when they match, so highlights
+// line up with what is searched.
+func (s *logSearch) matchText(text string) string {
+	if s == nil || s.re == nil || !strings.Contains(text, "\x1b") {
+		return text
+	}
+	if plain := stripANSI(text); s.re.MatchString(plain) {
+		return plain
+	}
+	return text
+}
+
+// locs returns the match ranges in a rendered chunk of line seq and
+// records them. nth is the running count of matches in the line so far.
+func (s *logSearch) locs(chunk string, row int, seq uint64, nth *int) (locs [][]int, cur int) {
+	cur = -1
+	if s == nil || s.re == nil || strings.Contains(chunk, "\x1b") {
+		return nil, cur
+	}
+	for _, loc := range s.re.FindAllStringIndex(chunk, -1) {
+		if loc[0] == loc[1] {
+			continue // empty matches would highlight nothing
+		}
+		if s.curSet && seq == s.curSeq && *nth == s.curNth {
+			cur = len(locs)
+			s.cur = len(s.matches)
+		}
+		s.matches = append(s.matches, searchMatch{row: row, seq: seq, nth: *nth})
+		locs = append(locs, loc)
+		*nth++
+	}
+	return locs, cur
+}
+
+// styleLogChunk colors a wrapped piece of a log line: the leading timestamp
+// dim, search matches highlighted (the current one distinctly) and the rest
+// by level. Lines that carry their own ANSI colors only get the level color.
+func (t Theme) styleLogChunk(chunk, text string, line LogLine, first bool, matches [][]int, cur int) string {
+	level, hasLevel := t.levelStyle(line.Level)
+	stampEnd := 0
+	if first && line.stampEnd > 0 && line.stampEnd <= len(chunk) && !strings.Contains(text, "\x1b") {
+		stampEnd = line.stampEnd
+	}
+	if stampEnd == 0 && len(matches) == 0 {
+		if hasLevel && chunk != "" {
+			return level.Render(chunk)
+		}
+		return chunk
+	}
+
+	stamp := lipgloss.NewStyle().Foreground(t.Muted)
+	hit := lipgloss.NewStyle().Background(t.Match).Foreground(t.MatchText)
+	current := lipgloss.NewStyle().Background(t.Current).Foreground(t.MatchText).Bold(true)
+	render := func(st lipgloss.Style, styled bool, s string) string {
+		if s == "" || !styled {
+			return s
+		}
+		return st.Render(s)
+	}
+
+	var b strings.Builder
+	pos := 0
+	plain := func(end int) {
+		if end <= pos {
+			return
+		}
+		if pos < stampEnd {
+			cut := end
+			if cut > stampEnd {
+				cut = stampEnd
+			}
+			b.WriteString(render(stamp, true, chunk[pos:cut]))
+			pos = cut
+		}
+		b.WriteString(render(level, hasLevel, chunk[pos:end]))
+		pos = end
+	}
+	for i, loc := range matches {
+		plain(loc[0])
+		if loc[0] < pos {
+			continue // overlaps previous
+		}
+		st := hit
+		if i == cur {
+			st = current
+		}
+		b.WriteString(st.Render(chunk[loc[0]:loc[1]]))
+		pos = loc[1]
+	}
+	plain(len(chunk))
+	return b.String()
+}
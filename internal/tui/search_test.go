@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_compileSearch_smartCase(t *testing.T) {
+	re, err := compileSearch("timeout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("Read TIMEOUT") {
+		t.Error("lowercase pattern should ignore case")
+	}
+	re, err = compileSearch("Timeout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if re.MatchString("read timeout") {
+		t.Error("mixed-case pattern should be case-sensitive")
+	}
+	if _, err := compileSearch("("); err == nil {
+		t.Error("want error for invalid regex")
+	}
+}
+
+func Test_search_navigation(t *testing.T) {
+	m := NewModel(nil, nil, nil, Options{})
+	m.viewport.Width = 200
+	m.viewport.Height = 5
+	for _, text := range []string{"err one", "ok", "err two err three", "ok"} {
+		m.appendLog(LogLine{Service: "api", Text: text})
+	}
+	m.renderViewport()
+
+	m.startSearch(`err \w+`)
+	if got := len(m.search.matches); got != 3 {
+		t.Fatalf("matches = %d, want 3", got)
+	}
+	if m.follow {
+		t.Error("follow should be off after jumping to a match")
+	}
+	if got := m.searchStatus(); got != `/err \w+/ 3/3` {
+		t.Errorf("searchStatus = %q", got)
+	}
+	m.searchStep(1)
+	if got := m.searchStatus(); !strings.HasSuffix(got, " 1/3") {
+		t.Errorf("after n (wrap) = %q", got)
+	}
+	m.searchStep(-1)
+	m.searchStep(-1)
+	if got := m.searchStatus(); !strings.HasSuffix(got, " 2/3") {
+		t.Errorf("after N N = %q", got)
+	}
+
+	// The current match is tracked by line, so new lines do not move it.
+	m.appendLog(LogLine{Service: "api", Text: "err four"})
+	m.renderViewport()
+	if got := m.searchStatus(); !strings.HasSuffix(got, " 2/4") {
+		t.Errorf("after new line = %q", got)
+	}
+	if plain := stripANSI(m.lastLogContent); !strings.Contains(plain, "err two err three") {
+		t.Errorf("highlighting should not change the text:\n%s", plain)
+	}
+
+	m.startSearch("(")
+	if got := m.searchStatus(); !strings.Contains(got, "invalid") {
+		t.Errorf("invalid regex status = %q", got)
+	}
+}
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"floppy-go/internal/procstats"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ServiceInfo is what the service detail panel shows. Env values are
+// expected to be masked by the caller.
+type ServiceInfo struct {
+	Name      string
+	Type      string
+	Dir       string
+	Command   string
+	PID       int
+	PGID      int
+	Running   bool
+	Env       []string
+	Ports     []string
+	StartedAt time.Time
+	Restarts  int
+	ExitCode  *int
+	OOMKilled bool
+}
+
+// serviceInfoMsg carries a service detail read by describeCmd.
+type serviceInfoMsg struct {
+	name string
+	info ServiceInfo
+	ok   bool
+}
+
+// describeCmd reads the detail of a service off the UI goroutine: describe
+// reads the state file under the manager's lock.
+func (m *Model) describeCmd(name string) tea.Cmd {
+	describe := m.describe
+	return func() tea.Msg {
+		info, ok := describe(name)
+		return serviceInfoMsg{name: name, info: info, ok: ok}
+	}
+}
+
+// openServiceInfo shows the detail panel for the service selected in the
+// status panel; the detail fills in when describeCmd returns.
+func (m *Model) openServiceInfo() tea.Cmd {
+	name, ok := m.selectedService()
+	if !ok || m.describe == nil {
+		return nil
+	}
+	m.serviceInfo = &ServiceInfo{Name: name}
+	m.renderServiceInfo()
+	m.infoViewport.GotoTop()
+	return m.describeCmd(name)
+}
+
+func (m *Model) closeServiceInfo() {
+	m.serviceInfo = nil
+}
+
+// refreshServiceInfo re-reads the open panel, following the status panel
+// selection.
+func (m *Model) refreshServiceInfo() tea.Cmd {
+	if m.serviceInfo == nil || m.describe == nil {
+		return nil
+	}
+	name, ok := m.selectedService()
+	if !ok {
+		return nil
+	}
+	if name != m.serviceInfo.Name {
+		m.serviceInfo = &ServiceInfo{Name: name}
+		m.renderServiceInfo()
+		m.infoViewport.GotoTop()
+	}
+	return m.describeCmd(name)
+}
+
+// setServiceInfo shows a detail from describeCmd unless the panel was
+// closed or moved to another service meanwhile.
+func (m *Model) setServiceInfo(msg serviceInfoMsg) {
+	if m.serviceInfo == nil || m.serviceInfo.Name != msg.name || !msg.ok {
+		return
+	}
+	m.serviceInfo = &msg.info
+	m.renderServiceInfo()
+}
+
+func (m *Model) renderServiceInfo() {
+	info := m.serviceInfo
+	width := m.infoViewport.Width
+	if width < 40 {
+		width = 80
+	}
+	label := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	row := func(name, value string) string {
+		return label.Render(fmt.Sprintf("%-10s", name)) + " " + value
+	}
+	dash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+
+	lines := []string{
+		lipgloss.NewStyle().Foreground(m.colorFor(info.Name)).Bold(true).Render(info.Name),
+		"",
+		row("Type", dash(info.Type)),
+		row("Cwd", dash(info.Dir)),
+		row("Command", dash(info.Command)),
+	}
+	process := "-"
+	if info.PID > 0 {
+		process = fmt.Sprintf("PID %d  PGID %d", info.PID, info.PGID)
+		if !info.Running {
+			process += label.Render("  (exited)")
+		}
+	}
+	lines = append(lines, row("Process", process))
+	uptime := "-"
+	if info.Running && !info.StartedAt.IsZero() {
+		uptime = procstats.FormatUptime(time.Since(info.StartedAt))
+	}
+	lines = append(lines,
+		row("Uptime", uptime),
+		row("Ports", dash(strings.Join(info.Ports, ", "))),
+		row("Restarts", fmt.Sprintf("%d", info.Restarts)),
+	)
+	exit := "-"
+	if info.ExitCode != nil {
+		exit = fmt.Sprintf("%d", *info.ExitCode)
+		if *info.ExitCode != 0 {
+			exit = lipgloss.NewStyle().Foreground(m.theme.Error).Render(exit)
+		}
+	}
+	if info.OOMKilled {
+		exit += lipgloss.NewStyle().Foreground(m.theme.Error).Render(" (OOM killed)")
+	}
+	lines = append(lines, row("Last exit", exit), "", label.Render("Environment"))
+	if len(info.Env) == 0 {
+		lines = append(lines, "  -")
+	}
+	for _, kv := range info.Env {
+		for _, chunk := range wrapRunes("  "+kv, width) {
+			lines = append(lines, chunk)
+		}
+	}
+	m.infoViewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// serviceInfoView is the panel shown in place of the left panel content.
+func (m *Model) serviceInfoView() string {
+	title := lipgloss.NewStyle().Foreground(m.theme.Dim).Render("Service detail ─ esc close • j/k select service • pgup/pgdown scroll")
+	return lipgloss.JoinVertical(lipgloss.Left, title, m.infoViewport.View())
+}
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func Test_serviceInfo_panel(t *testing.T) {
+	code := 1
+	describe := func(name string) (ServiceInfo, bool) {
+		return ServiceInfo{
+			Name: name, Type: "api", Command: "poetry run " + name, PID: 10, PGID: 10, Running: true,
+			StartedAt: time.Now().Add(-90 * time.Minute), Restarts: 1, ExitCode: &code,
+			Env: []string{"DB_PASSWORD=••••••"}, Ports: []string{"8000 (main)"},
+		}, true
+	}
+	rows := []ServiceRow{{Name: "api", Status: "running"}, {Name: "worker", Status: "running"}}
+	m := NewModel(nil, nil, rows, Options{Describe: describe})
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m.focusStatus = true
+	// describe runs as a command; deliver its result like the program would.
+	update := func(msg tea.Msg) {
+		if _, cmd := m.Update(msg); cmd != nil {
+			m.Update(cmd())
+		}
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.serviceInfo == nil || m.serviceInfo.Name != "api" {
+		t.Fatalf("enter should open the api detail, got %+v", m.serviceInfo)
+	}
+	view := stripANSI(m.View())
+	for _, want := range []string{"poetry run api", "PID 10  PGID 10", "1h30m", "8000 (main)", "DB_PASSWORD=••••••"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("detail missing %q", want)
+		}
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if m.serviceInfo.Name != "worker" || m.serviceInfo.Command != "poetry run worker" {
+		t.Errorf("moving the selection should follow: %+v", m.serviceInfo)
+	}
+
+	// A detail that arrives after the selection moved on is dropped.
+	m.Update(serviceInfoMsg{name: "api", info: ServiceInfo{Name: "api"}, ok: true})
+	if m.serviceInfo.Name != "worker" {
+		t.Errorf("stale detail replaced the panel: %q", m.serviceInfo.Name)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.serviceInfo != nil {
+		t.Error("esc should close the detail")
+	}
+}
@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxPanes is how many services the split view tiles.
+const maxPanes = 4
+
+// logPane is one service's logs in the split view, scrolled and followed
+// independently of the combined stream.
+type logPane struct {
+	service  string
+	viewport viewport.Model
+	follow   bool
+	// Outer rectangle (border included) relative to the log content area,
+	// for mouse hit-testing.
+	x, y, w, h int
+}
+
+// selectedService is the service under the cursor in the status panel.
+func (m *Model) selectedService() (string, bool) {
+	rows := m.sortedRows()
+	if m.filterText != "" {
+		needle := strings.ToLower(m.filterText)
+		filtered := rows[:0]
+		for _, row := range rows {
+			if strings.Contains(strings.ToLower(row.Name), needle) {
+				filtered = append(filtered, row)
+			}
+		}
+		rows = filtered
+	}
+	if m.selected < 0 || m.selected >= len(rows) {
+		return "", false
+	}
+	return rows[m.selected].Name, true
+}
+
+// togglePin adds the selected service to the split view, or removes it.
+func (m *Model) togglePin() {
+	name, ok := m.selectedService()
+	if !ok {
+		return
+	}
+	for i, p := range m.panes {
+		if p.service == name {
+			m.panes = append(m.panes[:i], m.panes[i+1:]...)
+			if m.activePane >= len(m.panes) {
+				m.activePane = len(m.panes) - 1
+			}
+			if len(m.panes) < 2 {
+				m.split = false
+			}
+			m.layoutPanes()
+			return
+		}
+	}
+	if len(m.panes) >= maxPanes {
+		return
+	}
+	m.panes = append(m.panes, &logPane{service: name, viewport: viewport.New(10, 5), follow: true})
+	m.layoutPanes()
+	m.renderViewport()
+}
+
+func (m *Model) paneIndex(service string) int {
+	for i, p := range m.panes {
+		if p.service == service {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleSplit switches between the combined stream and the tiled panes. The
+// split view needs at least two pinned services.
+func (m *Model) toggleSplit() {
+	if !m.split && len(m.panes) < 2 {
+		return
+	}
+	m.split = !m.split
+	if m.split && m.detail != nil {
+		m.closeDetail()
+	}
+	if m.activePane < 0 {
+		m.activePane = 0
+	}
+	m.layoutPanes()
+	m.renderViewport()
+}
+
+// layoutPanes tiles the panes over the log area: two side by side, three as
+// two on top and one full width below, four as a 2x2 grid.
+func (m *Model) layoutPanes() {
+	n := len(m.panes)
+	if n == 0 {
+		return
+	}
+	width, height := m.viewport.Width, m.pgViewport.Height
+	rows := (n + 1) / 2
+	for i, p := range m.panes {
+		row, col := i/2, i%2
+		p.y = row * (height / rows)
+		p.h = height / rows
+		if row == rows-1 {
+			p.h = height - p.y
+		}
+		p.x, p.w = 0, width
+		if n > 1 && !(n%2 == 1 && i == n-1) {
+			p.x = col * (width / 2)
+			p.w = width / 2
+			if col == 1 {
+				p.w = width - p.x
+			}
+		}
+		p.viewport.Width = max(p.w-2, 10)
+		p.viewport.Height = max(p.h-3, 1) // border and title
+	}
+}
+
+// renderPanes refreshes every pane from the log buffer.
+func (m *Model) renderPanes() {
+	for _, p := range m.panes {
+		service := p.service
+		lines, _ := m.renderLogLines(func(line LogLine) bool { return line.Service == service }, p.viewport.Width, nil)
+		p.viewport.SetContent(strings.Join(lines, "\n"))
+		if p.follow {
+			p.viewport.GotoBottom()
+		}
+	}
+}
+
+// splitView draws the panes in the grid laid out by layoutPanes.
+func (m *Model) splitView() string {
+	var rows []string
+	var row []string
+	for i, p := range m.panes {
+		row = append(row, m.renderPane(i, p))
+		if i%2 == 1 || i == len(m.panes)-1 {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row = nil
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m *Model) renderPane(i int, p *logPane) string {
+	border := m.theme.Border
+	if i == m.activePane && !m.focusStatus {
+		border = m.colorFor(p.service)
+	}
+	state := "following"
+	if !p.follow {
+		state = fmt.Sprintf("scrolled %d%%", int(p.viewport.ScrollPercent()*100))
+	}
+	title := lipgloss.NewStyle().Foreground(m.colorFor(p.service)).Bold(true).Render(p.service) +
+		lipgloss.NewStyle().Foreground(m.theme.Dim).Render(" · "+state)
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(border)
+	return box.Width(p.w - 2).Height(p.h - 2).Render(lipgloss.JoinVertical(lipgloss.Left, title, p.viewport.View()))
+}
+
+// paneFocused reports whether keys go to the active pane of the split view.
+func (m *Model) paneFocused() bool {
+	return m.split && !m.focusStatus && m.activeTab == TabAppLogs &&
+		m.activePane >= 0 && m.activePane < len(m.panes)
+}
+
+// paneKey runs a scrolling action on the active pane of the split view.
+func (m *Model) paneKey(action string) tea.Cmd {
+	p := m.panes[m.activePane]
+	switch action {
+	case "next-pane":
+		m.activePane = (m.activePane + 1) % len(m.panes)
+	case "prev-pane":
+		m.activePane = (m.activePane + len(m.panes) - 1) % len(m.panes)
+	case "down", "up", "page-up", "page-down":
+		var cmd tea.Cmd
+		p.viewport, cmd = p.viewport.Update(actionMsg(action))
+		p.follow = p.viewport.AtBottom()
+		return cmd
+	case "top":
+		p.viewport.GotoTop()
+		p.follow = false
+	case "bottom":
+		p.viewport.GotoBottom()
+		p.follow = true
+	case "follow":
+		p.follow = !p.follow
+		if p.follow {
+			p.viewport.GotoBottom()
+		}
+	}
+	return nil
+}
+
+// paneMouse scrolls the pane under the mouse wheel; x and y are relative to
+// the log content area.
+func (m *Model) paneMouse(msg tea.MouseMsg, x, y int) tea.Cmd {
+	if msg.Button != tea.MouseButtonWheelUp && msg.Button != tea.MouseButtonWheelDown {
+		return nil
+	}
+	for i, p := range m.panes {
+		if x >= p.x && x < p.x+p.w && y >= p.y && y < p.y+p.h {
+			var cmd tea.Cmd
+			p.viewport, cmd = p.viewport.Update(msg)
+			p.follow = p.viewport.AtBottom()
+			m.activePane = i
+			return cmd
+		}
+	}
+	return nil
+}
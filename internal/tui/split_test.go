@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func splitModel(t *testing.T, services ...string) *Model {
+	t.Helper()
+	rows := []ServiceRow{}
+	for _, s := range services {
+		rows = append(rows, ServiceRow{Name: s, Status: "running"})
+	}
+	m := NewModel(nil, nil, rows, Options{})
+	m.Update(tea.WindowSizeMsg{Width: 172, Height: 40})
+	m.focusStatus = true
+	for i := range services {
+		m.selected = i
+		m.togglePin()
+	}
+	m.focusStatus = false
+	m.toggleSplit()
+	return m
+}
+
+func Test_split_layout(t *testing.T) {
+	m := splitModel(t, "api", "portal", "worker")
+	if !m.split || len(m.panes) != 3 {
+		t.Fatalf("split=%v panes=%d", m.split, len(m.panes))
+	}
+	w, h := m.viewport.Width, m.pgViewport.Height
+	a, b, c := m.panes[0], m.panes[1], m.panes[2]
+	if a.x != 0 || b.x != a.w || a.w+b.w != w || a.y != 0 || b.y != 0 {
+		t.Errorf("top row: a=%+v b=%+v width=%d", *a, *b, w)
+	}
+	if c.x != 0 || c.w != w || c.y != a.h || c.y+c.h != h {
+		t.Errorf("bottom pane should span the width: c=%+v width=%d height=%d", *c, w, h)
+	}
+	if got := strings.Count(m.splitView(), "\n") + 1; got != h {
+		t.Errorf("split view is %d rows, want %d", got, h)
+	}
+}
+
+func Test_split_independentFollow(t *testing.T) {
+	m := splitModel(t, "api", "portal")
+	for i := 0; i < 100; i++ {
+		m.appendLog(LogLine{Service: "api", Text: fmt.Sprintf("api %d", i)})
+		m.appendLog(LogLine{Service: "portal", Text: fmt.Sprintf("portal %d", i)})
+	}
+	m.renderViewport()
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if m.panes[0].follow || !m.panes[1].follow {
+		t.Fatalf("g should only stop the active pane following: %v %v", m.panes[0].follow, m.panes[1].follow)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	if m.activePane != 1 {
+		t.Errorf("activePane = %d", m.activePane)
+	}
+	m.appendLog(LogLine{Service: "portal", Text: "portal new"})
+	m.renderViewport()
+	if !strings.Contains(m.panes[1].viewport.View(), "portal new") {
+		t.Error("following pane should show the newest line")
+	}
+	if view := m.panes[0].viewport.View(); !strings.Contains(view, "api 0") || strings.Contains(view, "portal") {
+		t.Errorf("api pane should stay at the top with only api lines:\n%s", view)
+	}
+
+	// Unpinning down to one service leaves the split view.
+	m.focusStatus = true
+	m.selected = 0
+	m.togglePin()
+	if m.split {
+		t.Error("split view needs two panes")
+	}
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the set of colors the TUI draws with.
+type Theme struct {
+	Border    lipgloss.Color // panel borders and separators
+	Dim       lipgloss.Color // labels, hints and inactive tabs
+	Muted     lipgloss.Color // timestamps, stopped services, debug lines
+	Active    lipgloss.Color // the active tab
+	OK        lipgloss.Color
+	Warn      lipgloss.Color
+	Error     lipgloss.Color
+	Match     lipgloss.Color // search hit background
+	MatchText lipgloss.Color
+	Current   lipgloss.Color // current search hit background
+	Palette   []lipgloss.Color
+}
+
+var (
+	darkTheme = Theme{
+		Border: "240", Dim: "245", Muted: "8", Active: "7",
+		OK: "2", Warn: "3", Error: "1",
+		Match: "3", MatchText: "0", Current: "208",
+		Palette: []lipgloss.Color{"2", "3", "4", "5", "6", "9", "10", "11", "12", "13"},
+	}
+	// lightTheme avoids the pale yellows and greens that vanish on a white
+	// background.
+	lightTheme = Theme{
+		Border: "250", Dim: "242", Muted: "244", Active: "0",
+		OK: "28", Warn: "130", Error: "160",
+		Match: "222", MatchText: "0", Current: "208",
+		Palette: []lipgloss.Color{"25", "28", "90", "130", "31", "160", "55", "94", "24", "125"},
+	}
+)
+
+// ThemeByName returns the "dark" (default) or "light" theme.
+func ThemeByName(name string) (Theme, error) {
+	switch name {
+	case "", "dark":
+		return darkTheme, nil
+	case "light":
+		return lightTheme, nil
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q (want dark or light)", name)
+}
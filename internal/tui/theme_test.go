@@ -0,0 +1,17 @@
+package tui
+
+import "testing"
+
+func Test_NewModel_themePerModel(t *testing.T) {
+	light := NewModel(nil, nil, nil, Options{Theme: "light"})
+	dark := NewModel(nil, nil, nil, Options{})
+	if light.theme.Error != lightTheme.Error {
+		t.Errorf("light model error color = %q", light.theme.Error)
+	}
+	if dark.theme.Error != darkTheme.Error {
+		t.Errorf("a later model must not inherit another's theme: error color = %q", dark.theme.Error)
+	}
+	if light.theme.Error != lightTheme.Error {
+		t.Errorf("creating a model changed an existing model's theme")
+	}
+}
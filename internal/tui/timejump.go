@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseJumpTarget reads the time to jump to: a number of minutes ago ("15"),
+// a duration ago ("90s", "2h") or a clock time today ("14:05", "14:05:30";
+// yesterday when that is still in the future).
+func parseJumpTarget(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if n, err := strconv.Atoi(input); err == nil && n >= 0 {
+		return now.Add(-time.Duration(n) * time.Minute), nil
+	}
+	if d, err := time.ParseDuration(input); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		clock, err := time.ParseInLocation(layout, input, now.Location())
+		if err != nil {
+			continue
+		}
+		t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, now.Location())
+		if t.After(now) {
+			t = t.AddDate(0, 0, -1)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("want minutes ago, a duration like 10m or HH:MM")
+}
+
+// jumpToTime scrolls the combined log view to the first line received at or
+// after target, loading spilled history as needed.
+func (m *Model) jumpToTime(target time.Time) {
+	m.split = false
+	m.activeTab = TabAppLogs
+	for len(m.logs) > 0 && m.logs[0].Time.After(target) {
+		if m.loadSpilled() == 0 {
+			break
+		}
+	}
+	m.follow = false
+	m.renderViewport()
+	for row, idx := range m.lineIndex {
+		if !m.logs[idx].Time.Before(target) {
+			m.viewport.SetYOffset(row)
+			return
+		}
+	}
+	m.viewport.GotoBottom()
+}
+
+// applyJump handles the jump prompt input.
+func (m *Model) applyJump(input string) {
+	if strings.TrimSpace(input) == "" {
+		m.jumpErr = ""
+		return
+	}
+	target, err := parseJumpTarget(input, time.Now())
+	if err != nil {
+		m.jumpErr = err.Error()
+		return
+	}
+	m.jumpErr = ""
+	m.jumpToTime(target)
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseJumpTarget(t *testing.T) {
+	now := time.Date(2025, 3, 4, 10, 30, 0, 0, time.Local)
+	cases := map[string]time.Time{
+		"15":    now.Add(-15 * time.Minute),
+		"90s":   now.Add(-90 * time.Second),
+		"2h":    now.Add(-2 * time.Hour),
+		"10:05": time.Date(2025, 3, 4, 10, 5, 0, 0, time.Local),
+		"23:00": time.Date(2025, 3, 3, 23, 0, 0, 0, time.Local),
+	}
+	for in, want := range cases {
+		got, err := parseJumpTarget(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseJumpTarget(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseJumpTarget("soon", now); err == nil {
+		t.Error("want error")
+	}
+}
+
+func Test_jumpToTime(t *testing.T) {
+	m := NewModel(nil, nil, nil, Options{Scrollback: 10, SpillDir: t.TempDir()})
+	defer m.Close()
+	m.viewport.Width = 120
+	m.viewport.Height = 5
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 60; i++ {
+		m.appendLog(LogLine{Service: "api", Text: fmt.Sprintf("minute %d", i), Time: start.Add(time.Duration(i) * time.Minute)})
+	}
+	m.renderViewport()
+
+	// Minute 20 was spilled to disk long ago; jumping there loads it back.
+	m.jumpToTime(start.Add(20 * time.Minute))
+	if m.follow {
+		t.Error("follow should be off after a jump")
+	}
+	idx := m.lineIndex[m.viewport.YOffset]
+	if got := m.logs[idx].Text; got != "minute 20" {
+		t.Errorf("top line = %q, want minute 20", got)
+	}
+
+	m.showTimes = true
+	m.renderViewport()
+	stamp := start.Add(20 * time.Minute).Format("15:04:05.000")
+	if !strings.Contains(stripANSI(m.lastLogContent), stamp+" [api] minute 20") {
+		t.Errorf("timestamps not shown:\n%s", stripANSI(m.lastLogContent))
+	}
+}